@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type ByteSize int64
+
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+	PiB ByteSize = 1024 * TiB
+)
+
+var byteSizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"k":   KB,
+	"kb":  KB,
+	"m":   MB,
+	"mb":  MB,
+	"g":   GB,
+	"gb":  GB,
+	"t":   TB,
+	"tb":  TB,
+	"p":   PB,
+	"pb":  PB,
+	"kib": KiB,
+	"mib": MiB,
+	"gib": GiB,
+	"tib": TiB,
+	"pib": PiB,
+}
+
+func ParseByteSize(s string) (ByteSize, error) {
+	text := strings.TrimSpace(s)
+
+	i := len(text)
+	for i > 0 && !(text[i-1] >= '0' && text[i-1] <= '9' || text[i-1] == '.') {
+		i--
+	}
+
+	unit, ok := byteSizeUnits[strings.ToLower(strings.TrimSpace(text[i:]))]
+	if !ok {
+		return 0, errors.New("invalid byte size: " + s)
+	}
+
+	if n, err := strconv.ParseInt(text[:i], 10, 64); err == nil {
+		if n < 0 {
+			return 0, errors.New("negative byte size: " + s)
+		}
+
+		if n > math.MaxInt64/int64(unit) {
+			return 0, errors.New("byte size out of range: " + s)
+		}
+
+		return ByteSize(n) * unit, nil
+	}
+
+	n, err := strconv.ParseFloat(text[:i], 64)
+	if err != nil {
+		return 0, errors.New("invalid byte size: " + s)
+	}
+
+	if n < 0 {
+		return 0, errors.New("negative byte size: " + s)
+	}
+
+	// float64(math.MaxInt64) rounds up to 2^63, which does not fit in an int64
+	v := n * float64(unit)
+	if v >= math.MaxInt64 {
+		return 0, errors.New("byte size out of range: " + s)
+	}
+
+	return ByteSize(v), nil
+}
+
+func (size ByteSize) String() string {
+	units := []struct {
+		size ByteSize
+		name string
+	}{
+		{PB, "PB"}, {PiB, "PiB"},
+		{TB, "TB"}, {TiB, "TiB"},
+		{GB, "GB"}, {GiB, "GiB"},
+		{MB, "MB"}, {MiB, "MiB"},
+		{KB, "KB"}, {KiB, "KiB"},
+	}
+
+	for _, unit := range units {
+		if size != 0 && size%unit.size == 0 {
+			return strconv.FormatInt(int64(size/unit.size), 10) + unit.name
+		}
+	}
+
+	return strconv.FormatInt(int64(size), 10) + "B"
+}
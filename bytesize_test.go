@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ByteSize
+		err  bool
+	}{
+		{"0", 0, false},
+		{"512", 512, false},
+		{"512B", 512, false},
+		{"1k", KB, false},
+		{"1KB", KB, false},
+		{"1.5MB", 1500 * KB, false},
+		{"1KiB", KiB, false},
+		{"2 GiB", 2 * GiB, false},
+		{" 3tb ", 3 * TB, false},
+		{"1PiB", PiB, false},
+		{"9223372036854775807", math.MaxInt64, false},
+		{"9223372036854775808", 0, true},
+		{"9223372036854775807.0", 0, true},
+		{"9300PB", 0, true},
+		{"8192PiB", 0, true},
+		{"-1KB", 0, true},
+		{"-0.5", 0, true},
+		{"", 0, true},
+		{"KB", 0, true},
+		{"1XB", 0, true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseByteSize(test.in)
+		if (err != nil) != test.err {
+			t.Errorf("ParseByteSize(%q) error = %v, want error %v", test.in, err, test.err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseByteSize(%q) = %d, want %d", test.in, got, test.want)
+		}
+	}
+}
+
+func TestByteSizeString(t *testing.T) {
+	tests := []struct {
+		in   ByteSize
+		want string
+	}{
+		{0, "0B"},
+		{512, "512B"},
+		{KB, "1KB"},
+		{KiB, "1KiB"},
+		{1500 * KB, "1500KB"},
+		{2 * GiB, "2GiB"},
+		{3 * TB, "3TB"},
+		{PB, "1PB"},
+		{1025, "1025B"},
+	}
+
+	for _, test := range tests {
+		if got := test.in.String(); got != test.want {
+			t.Errorf("ByteSize(%d).String() = %q, want %q", int64(test.in), got, test.want)
+		}
+	}
+}
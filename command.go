@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -22,60 +23,99 @@ type Command struct {
 	Commands    []*Command
 	Action      func(*Context) error
 
-	Copyright string
-	Version   string
-	NoHelp    bool
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	Copyright    string
+	Version      string
+	VersionShort string
+	NoHelp       bool
+	EnvPrefix    string
+	StrictPOSIX  bool
+	Abbreviation bool
+	Debug        bool
+
+	ConfigFile string
 }
 
 func (command *Command) Run(args []string, defaultAction func(*Context) error) error {
-	if defaultAction == nil {
-		defaultAction = ShowHelp(os.Stdout)
+	if command.Debug {
+		if err := command.Validate(); err != nil {
+			return err
+		}
 	}
+
 	return command.run(nil, args, defaultAction)
 }
 
 func (command *Command) run(parent *Context, args []string, defaultAction func(*Context) error) error {
 	args = args[1:]
 
-	if command.Version != "" {
-		command.Options = append(command.Options, &BoolOption{
-			Name:        "version",
-			Short:       "v",
-			Description: "show version",
-		})
-	}
-
-	if !command.NoHelp {
-		command.Options = append(command.Options, &BoolOption{
-			Name:        "help",
-			Short:       "h",
-			Description: "show help",
-		})
-	}
-
 	context := &Context{
-		parent:  parent,
-		command: command,
-		options: map[string]interface{}{},
-		args:    []string{},
+		parent:   parent,
+		command:  command,
+		flags:    command.allOptions(parent == nil),
+		options:  map[string]interface{}{},
+		defaults: map[string]interface{}{},
+		sources:  map[string]source{},
+		args:     []string{},
 	}
 
 	options := map[string]Option{}
-	for _, option := range command.Options {
-		option.SetDefaultValue(context.options)
+	for _, option := range context.persistentOptions() {
+		for _, keyword := range option.Keywords() {
+			options[keyword] = option
+		}
+	}
+
+	for _, option := range context.flags {
+		option.SetDefaultValue(context.defaults)
 
 		for _, keyword := range option.Keywords() {
 			options[keyword] = option
 		}
 	}
 
+	for key, value := range context.defaults {
+		context.sources[key] = source{SourceDefault, fmt.Sprint(value)}
+	}
+
+	values := map[string]interface{}{}
+	raws := map[string]string{}
+
+	negativeArgs := true
+	for keyword := range options {
+		if len(keyword) == 2 && keyword[0] == '-' && keyword[1] >= '0' && keyword[1] <= '9' {
+			negativeArgs = false
+		}
+	}
+
 	i := 0
+	positionals := []string{}
 
 PARSE_OPTIONS:
 	for i < len(args) {
 		switch {
 		default:
-			break PARSE_OPTIONS
+			// positional argument
+			if command.StrictPOSIX {
+				break PARSE_OPTIONS
+			}
+
+			if len(positionals) == 0 {
+				subcommand, err := context.subcommand(args[i])
+				if err != nil {
+					return err
+				}
+
+				if subcommand != nil {
+					break PARSE_OPTIONS
+				}
+			}
+
+			positionals = append(positionals, args[i])
+			i++
 
 		case args[i] == "--":
 			// end of option list
@@ -87,37 +127,60 @@ PARSE_OPTIONS:
 
 			if j := strings.Index(args[i], "="); j >= 0 {
 				// --key=value
-				key := args[i][:j]
+				token := args[i][:j]
 				value := args[i][j+1:]
 				i++
 
-				option, ok := options[key]
-				if !ok {
-					return errors.New("unknown option: " + key)
+				key, option, err := context.longOption(options, token)
+				if err != nil {
+					return err
 				}
 
-				if _, err := option.Apply(context.options, value); err != nil {
-					return err
+				if isNegated(option, key) {
+					return &InvalidValueError{Command: context.Name(), Option: option, Token: value, Err: errors.New("negated flag does not take a value")}
+				}
+
+				if _, err := option.Apply(context.target(option, values), value); err != nil {
+					return context.optionError(err, token)
 				}
 
+				raws[optionKey(option)] = value
+
 			} else {
 				// --key value
-				key := args[i]
+				token := args[i]
 				i++
 
-				option, ok := options[key]
-				if !ok {
-					return errors.New("unknown option: " + key)
+				key, option, err := context.longOption(options, token)
+				if err != nil {
+					return err
 				}
 
-				n, err := option.Apply(context.options, args[i:]...)
-				i += n
+				if isNegated(option, key) {
+					if _, err := option.Apply(context.target(option, values), "false"); err != nil {
+						return context.optionError(err, token)
+					}
+
+					raws[optionKey(option)] = key
+					continue
+				}
+
+				rest := separatedValue(args[i:])
+				if isBoolFlag(option) {
+					rest = nil
+				}
+
+				n, err := option.Apply(context.target(option, values), rest...)
 				if err != nil {
-					return err
+					return context.optionError(err, token)
 				}
+				n = consumed(n, rest)
+
+				raws[optionKey(option)] = rawValue(key, rest[:n])
+				i += n
 			}
 
-		case len(args[i]) >= 2 && args[i][0] == '-':
+		case len(args[i]) >= 2 && args[i][0] == '-' && !(negativeArgs && isNegativeNumber(args[i])):
 			// short option
 			arg := args[i][1:]
 			i++
@@ -127,55 +190,139 @@ PARSE_OPTIONS:
 
 				option, ok := options[key]
 				if !ok {
-					return errors.New("unknown option: " + key)
+					return &UnknownOptionError{Command: context.Name(), Token: key, Suggestions: shortSuggestions(key, options)}
+				}
+
+				if isBoolFlag(option) {
+					if _, err := option.Apply(context.target(option, values)); err != nil {
+						return context.optionError(err, key)
+					}
+
+					raws[optionKey(option)] = key
+					continue
 				}
 
 				if j == len(arg)-1 {
-					n, err := option.Apply(context.options, args[i:]...)
+					rest := separatedValue(args[i:])
+
+					n, err := option.Apply(context.target(option, values), rest...)
 					if err != nil {
-						return err
+						return context.optionError(err, key)
 					}
+					n = consumed(n, rest)
 
+					raws[optionKey(option)] = rawValue(key, rest[:n])
 					i += n
 
 					break
 				}
 
-				n, err := option.Apply(context.options, arg[j+1:])
+				n, err := option.Apply(context.target(option, values), arg[j+1:])
 				if err != nil {
-					return err
+					return context.optionError(err, key)
 				}
 
 				if n > 0 {
+					raws[optionKey(option)] = arg[j+1:]
 					break
 				}
+
+				raws[optionKey(option)] = key
 			}
 		}
 	}
 
-	if command.Version != "" && context.IsSet("version") {
-		fmt.Fprintln(os.Stdout, command.Version)
+	if v, _ := values["version"].(bool); v && command.Version != "" {
+		fmt.Fprintln(context.Stdout(), command.Version)
 		return nil
 	}
 
-	if !command.NoHelp && context.IsSet("help") {
-		return context.ShowHelp(os.Stdout)
+	if v, _ := values["help"].(bool); v && !command.NoHelp {
+		return context.ShowHelp(context.Stdout())
+	}
+
+	// config file
+	if err := context.readConfig(values); err != nil {
+		return err
+	}
+
+	configs := map[string]interface{}{}
+	for _, option := range context.flags {
+		value, ok := context.config[optionKey(option)]
+		if !ok || command.isBuiltinOption(option) {
+			continue
+		}
+
+		vs, err := configValues(value, configTimeLayout(option))
+		if err == nil {
+			for _, v := range vs {
+				if _, err = option.Apply(configs, v); err != nil {
+					break
+				}
+			}
+		}
+
+		if err != nil {
+			return fmt.Errorf("invalid config value %s: %w", context.configKey(option), context.optionError(err, context.configKey(option)))
+		}
+
+		context.sources[optionKey(option)] = source{SourceConfigFile, strings.Join(vs, ",")}
 	}
 
-	context.args = args[i:]
+	// environment variables
+	envs := map[string]interface{}{}
+	for _, option := range context.flags {
+		for _, env := range context.envKeywords(option) {
+			value := os.Getenv(env)
+			if value == "" {
+				continue
+			}
+
+			if _, err := option.Apply(envs, value); err != nil {
+				return fmt.Errorf("invalid environment variable %s: %w", env, context.optionError(err, env))
+			}
+
+			context.sources[optionKey(option)] = source{SourceEnv, value}
+
+			break
+		}
+	}
+
+	for _, options := range []map[string]interface{}{configs, envs, values} {
+		for key, value := range options {
+			context.options[key] = value
+		}
+	}
+
+	for key, raw := range raws {
+		context.owner(key).sources[key] = source{SourceCommandLine, raw}
+	}
+
+	context.args = append(positionals, args[i:]...)
 
 	// sub command
 	if len(context.args) > 0 {
+		subcommand, err := context.subcommand(context.args[0])
+		if err != nil {
+			return err
+		}
+
+		if subcommand != nil {
+			return subcommand.run(context, context.args, defaultAction)
+		}
+	}
+
+	if command.Action == nil && len(command.Commands) > 0 && len(context.args) > 0 {
+		candidates := []string{}
 		for _, subcommand := range command.Commands {
-			if subcommand.Name == context.args[0] {
-				return subcommand.run(context, context.args, defaultAction)
-			}
+			candidates = append(candidates, subcommand.Name)
+			candidates = append(candidates, subcommand.Aliases...)
+		}
 
-			for _, alias := range subcommand.Aliases {
-				if alias == context.args[0] {
-					return subcommand.run(context, context.args, defaultAction)
-				}
-			}
+		context.suggestions = suggest(context.args[0], candidates)
+
+		if defaultAction == nil {
+			return &UnknownCommandError{Command: context.Name(), Token: context.args[0], Suggestions: context.suggestions}
 		}
 	}
 
@@ -184,7 +331,11 @@ PARSE_OPTIONS:
 			return defaultAction(context)
 		}
 
-		return nil
+		return ShowHelp(context.Stdout())(context)
+	}
+
+	if err := context.checkRequired(); err != nil {
+		return err
 	}
 
 	if err := command.Action(context); err != nil {
@@ -194,11 +345,273 @@ PARSE_OPTIONS:
 	return nil
 }
 
+func (context *Context) checkRequired() error {
+	missing := []Option{}
+
+	for c := context; c != nil; c = c.parent {
+		for _, option := range c.flags {
+			if isRequired(option) && !context.IsSet(optionKey(option)) {
+				missing = append(missing, option)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return &RequiredOptionsError{Command: context.Name(), Options: missing}
+	}
+
+	return nil
+}
+
+func (context *Context) owner(key string) *Context {
+	for c := context; c != nil; c = c.parent {
+		for _, option := range c.flags {
+			if optionKey(option) == key && (c == context || isPersistent(option)) {
+				return c
+			}
+		}
+	}
+	return context
+}
+
+func (context *Context) target(option Option, values map[string]interface{}) map[string]interface{} {
+	key := optionKey(option)
+
+	owner := context.owner(key)
+	if owner == context {
+		return values
+	}
+
+	// persistent options given after a subcommand add to the value on the command line of the declaring command
+	if owner.sources[key].source != SourceCommandLine {
+		delete(owner.options, key)
+		owner.sources[key] = source{SourceCommandLine, ""}
+	}
+
+	return owner.options
+}
+
+func (context *Context) subcommand(name string) (*Command, error) {
+	for _, subcommand := range context.command.Commands {
+		if subcommand.Name == name {
+			return subcommand, nil
+		}
+
+		for _, alias := range subcommand.Aliases {
+			if alias == name {
+				return subcommand, nil
+			}
+		}
+	}
+
+	if !context.abbreviation() {
+		return nil, nil
+	}
+
+	candidates := []string{}
+	var found *Command
+
+	for _, subcommand := range context.command.Commands {
+		for _, n := range append([]string{subcommand.Name}, subcommand.Aliases...) {
+			if strings.HasPrefix(n, name) {
+				if found != subcommand {
+					candidates = append(candidates, n)
+				}
+				found = subcommand
+			}
+		}
+	}
+
+	if len(candidates) > 1 {
+		return nil, &AmbiguousCommandError{Command: context.Name(), Token: name, Candidates: candidates}
+	}
+
+	return found, nil
+}
+
+func (context *Context) longOption(options map[string]Option, key string) (string, Option, error) {
+	if option, ok := options[key]; ok {
+		return key, option, nil
+	}
+
+	if context.abbreviation() {
+		candidates := []string{}
+		for keyword := range options {
+			if len(keyword) > 2 && keyword[:2] == "--" && strings.HasPrefix(keyword, key) {
+				candidates = append(candidates, keyword)
+			}
+		}
+
+		sort.Strings(candidates)
+
+		if len(candidates) == 1 {
+			return candidates[0], options[candidates[0]], nil
+		}
+
+		if len(candidates) > 1 {
+			return "", nil, &AmbiguousOptionError{Command: context.Name(), Token: key, Candidates: candidates}
+		}
+	}
+
+	candidates := []string{}
+	for keyword := range options {
+		if len(keyword) > 2 && keyword[:2] == "--" {
+			candidates = append(candidates, keyword)
+		}
+	}
+
+	return "", nil, &UnknownOptionError{Command: context.Name(), Token: key, Suggestions: suggest(key, candidates)}
+}
+
+func (context *Context) abbreviation() bool {
+	for c := context; c != nil; c = c.parent {
+		if c.command.Abbreviation {
+			return true
+		}
+	}
+	return false
+}
+
+func (command *Command) allOptions(root bool) []Option {
+	options := append([]Option{}, command.Options...)
+
+	if command.Version != "" {
+		short := command.versionShort()
+		if command.hasKeyword("-" + short) {
+			short = ""
+		}
+
+		options = append(options, &BoolOption{
+			Name:        "version",
+			Short:       short,
+			Description: "show version",
+		})
+	}
+
+	if root && command.ConfigFile != "" {
+		options = append(options, &StringOption{
+			Name:        "config",
+			Description: "load config file",
+			ArgUsage:    "path",
+		})
+	}
+
+	if !command.NoHelp {
+		options = append(options, &BoolOption{
+			Name:        "help",
+			Short:       "h",
+			Description: "show help",
+		})
+	}
+
+	return options
+}
+
+func (command *Command) versionShort() string {
+	if command.VersionShort != "" {
+		return command.VersionShort
+	}
+	return "v"
+}
+
+func (command *Command) hasKeyword(keyword string) bool {
+	for _, option := range command.Options {
+		for _, k := range option.Keywords() {
+			if k == keyword {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isBoolFlag(option Option) bool {
+	flag, ok := option.(interface{ IsBoolFlag() bool })
+	return ok && flag.IsBoolFlag()
+}
+
+func optionKey(option Option) string {
+	if o, ok := option.(interface{ Key() string }); ok {
+		return o.Key()
+	}
+
+	key := ""
+	for _, keyword := range option.Keywords() {
+		if strings.HasPrefix(keyword, "--") {
+			return keyword[2:]
+		}
+		if key == "" {
+			key = strings.TrimPrefix(keyword, "-")
+		}
+	}
+	return key
+}
+
+func optionEnvKeywords(option Option) []string {
+	o, ok := option.(interface{ EnvKeywords() []string })
+	if !ok {
+		return nil
+	}
+	return o.EnvKeywords()
+}
+
+func isRequired(option Option) bool {
+	o, ok := option.(interface{ IsRequired() bool })
+	return ok && o.IsRequired()
+}
+
+func isPersistent(option Option) bool {
+	o, ok := option.(interface{ IsPersistent() bool })
+	return ok && o.IsPersistent()
+}
+
+func isNegated(option Option, keyword string) bool {
+	return isBoolFlag(option) && keyword == "--no-"+optionKey(option)
+}
+
+func separatedValue(args []string) []string {
+	// a separated value must not look like an option, unlike --key=value, -kvalue, env and config values
+	if len(args) > 0 && len(args[0]) >= 2 && args[0][0] == '-' && !isNegativeNumber(args[0]) {
+		return nil
+	}
+	return args
+}
+
+func consumed(n int, args []string) int {
+	if n < 0 {
+		return 0
+	}
+	if n > len(args) {
+		return len(args)
+	}
+	return n
+}
+
+func rawValue(keyword string, args []string) string {
+	if len(args) == 0 {
+		return keyword
+	}
+	return strings.Join(args, " ")
+}
+
+func (command *Command) isBuiltinOption(option Option) bool {
+	switch optionKey(option) {
+	case "config":
+		return command.ConfigFile != ""
+	case "version":
+		return command.Version != ""
+	case "help":
+		return !command.NoHelp
+	default:
+		return false
+	}
+}
+
 func ShowHelp(out io.Writer) func(*Context) error {
 	return func(context *Context) error {
 		if err := context.ShowHelp(out); err != nil {
 			return err
 		}
-		return errors.New("invalid arguments")
+		return ErrInvalidArguments
 	}
 }
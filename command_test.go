@@ -0,0 +1,582 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type labels map[string]string
+
+func (l labels) Set(s string) error {
+	key, value, err := splitKeyValue(s)
+	if err != nil {
+		return err
+	}
+	l[key] = value
+	return nil
+}
+
+func (l labels) String() string {
+	pairs := []string{}
+	for key, value := range l {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+type port int
+
+func (p *port) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	*p = port(n)
+	return err
+}
+
+func (p *port) String() string {
+	return strconv.Itoa(int(*p))
+}
+
+func TestRunConcurrently(t *testing.T) {
+	var ip net.IP
+	defaultPort := port(80)
+
+	command := &Command{
+		Name:    "app",
+		Version: "v1.0.0",
+		Stdout:  io.Discard,
+		Options: []Option{
+			&StringOption{Name: "name", Persistent: true},
+			&StringSliceOption{Name: "tag"},
+			&ValueOption{Name: "label", Value: labels{}, New: func() flag.Value { return labels{} }},
+			&ValueOption{Name: "ip", Value: TextValue(&ip), New: func() flag.Value { return TextValue(new(net.IP)) }},
+			&ValueOption{Name: "port", Value: &defaultPort},
+		},
+		Commands: []*Command{
+			{
+				Name:    "sub",
+				Options: []Option{&IntOption{Name: "n", DefaultValue: 1}},
+				Action: func(context *Context) error {
+					want := context.Args()[0]
+					if got := context.String("name"); got != want {
+						return fmt.Errorf("name = %q, want %q", got, want)
+					}
+					if got := context.Value("label").(labels); len(got) != 1 || got[want] != want {
+						return fmt.Errorf("label = %v, want %s=%s", got, want, want)
+					}
+					if got := *context.Value("port").(*port); fmt.Sprint("run", int(got)-8000) != want {
+						return fmt.Errorf("port = %d, want for %s", got, want)
+					}
+					if got := context.StringSlice("tag"); len(got) != 1 || got[0] != want {
+						return fmt.Errorf("tag = %v, want [%s]", got, want)
+					}
+					return nil
+				},
+			},
+		},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s := fmt.Sprint("run", i)
+			args := []string{"app", "--name", s, "--tag", s, "--label", s + "=" + s, "--ip", fmt.Sprint("10.0.0.", i), "--port", fmt.Sprint(8000 + i), "sub", s}
+			if err := command.Run(args, nil); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if len(command.Options) != 5 {
+		t.Errorf("Options was modified: %d options", len(command.Options))
+	}
+
+	if got := command.Options[2].(*ValueOption).Value.(labels); len(got) != 0 {
+		t.Errorf("ValueOption.Value was modified: %v", got)
+	}
+
+	if ip != nil {
+		t.Errorf("TextValue target was modified: %v", ip)
+	}
+
+	if defaultPort != 80 {
+		t.Errorf("ValueOption.Value was modified: %v", defaultPort)
+	}
+}
+
+type legacyOption struct{}
+
+func (option *legacyOption) SetDefaultValue(options map[string]interface{}) {}
+
+func (option *legacyOption) Keywords() []string {
+	return []string{"-l", "--legacy"}
+}
+
+func (option *legacyOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+	options["legacy"] = args[0]
+	return 1, nil
+}
+
+func (option *legacyOption) Help() [2]string {
+	return [2]string{"-l,--legacy=value", "legacy option"}
+}
+
+func TestLegacyOption(t *testing.T) {
+	var got string
+	var source ValueSource
+
+	command := &Command{
+		Name:    "app",
+		Options: []Option{&legacyOption{}},
+		Action: func(context *Context) error {
+			got = context.String("legacy")
+			source, _ = context.Source("legacy")
+			return nil
+		},
+	}
+
+	if err := command.Run([]string{"app", "--legacy", "x"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if got != "x" || source != SourceCommandLine {
+		t.Errorf("legacy = %q from %v, want \"x\" from command line", got, source)
+	}
+}
+
+func TestEnvValueWithLeadingDash(t *testing.T) {
+	t.Setenv("TOKEN", "-abc")
+
+	var got string
+	command := &Command{
+		Name:    "app",
+		Options: []Option{&StringOption{Name: "token", EnvVars: []string{"TOKEN"}}},
+		Action: func(context *Context) error {
+			got = context.String("token")
+			return nil
+		},
+	}
+
+	if err := command.Run([]string{"app"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if got != "-abc" {
+		t.Errorf("token = %q, want \"-abc\"", got)
+	}
+
+	var missing *MissingValueError
+	if err := command.Run([]string{"app", "--token", "-x"}, nil); !errors.As(err, &missing) {
+		t.Errorf("--token -x: got %v, want MissingValueError", err)
+	}
+}
+
+func TestConfigValueWithLeadingDash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("name: -x\ntags: [-a, b]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var name string
+	var tags []string
+	command := &Command{
+		Name:       "app",
+		ConfigFile: "app",
+		Options: []Option{
+			&StringOption{Name: "name"},
+			&StringSliceOption{Name: "tags"},
+		},
+		Action: func(context *Context) error {
+			name = context.String("name")
+			tags = context.StringSlice("tags")
+			return nil
+		},
+	}
+
+	if err := command.Run([]string{"app", "--config", path}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if name != "-x" || strings.Join(tags, ",") != "-a,b" {
+		t.Errorf("name = %q, tags = %v, want \"-x\", [-a b]", name, tags)
+	}
+}
+
+func TestNegativeNumbers(t *testing.T) {
+	var flags []bool
+	var timeout time.Duration
+	var n int
+	var args []string
+
+	command := &Command{
+		Name: "app",
+		Options: []Option{
+			&BoolOption{Name: "include", Short: "I"},
+			&BoolOption{Name: "dry-run", Short: "n"},
+			&BoolOption{Name: "force", Short: "f"},
+			&DurationOption{Name: "timeout"},
+			&IntOption{Name: "n"},
+		},
+		Action: func(context *Context) error {
+			flags = []bool{context.Bool("include"), context.Bool("dry-run"), context.Bool("force")}
+			timeout = context.Duration("timeout")
+			n = context.Int("n")
+			args = context.Args()
+			return nil
+		},
+	}
+
+	if err := command.Run([]string{"app", "-Inf", "--timeout", "-5s", "--n", "-3", "-.5"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if !flags[0] || !flags[1] || !flags[2] {
+		t.Errorf("-Inf: flags = %v, want all set", flags)
+	}
+
+	if timeout != -5*time.Second || n != -3 {
+		t.Errorf("timeout = %v, n = %d, want -5s, -3", timeout, n)
+	}
+
+	if len(args) != 1 || args[0] != "-.5" {
+		t.Errorf("args = %v, want [-.5]", args)
+	}
+}
+
+func TestOptBuiltinTypes(t *testing.T) {
+	var n int32
+	var f float32
+	var at time.Time
+	var b bool
+	var args []string
+
+	command := &Command{
+		Name: "app",
+		Options: []Option{
+			&Opt[int32]{Name: "n"},
+			&Opt[float32]{Name: "f"},
+			&Opt[time.Time]{Name: "at"},
+			&Opt[bool]{Name: "b"},
+		},
+		Action: func(context *Context) error {
+			n = Get[int32](context, "n")
+			f = Get[float32](context, "f")
+			at = Get[time.Time](context, "at")
+			b = Get[bool](context, "b")
+			args = context.Args()
+			return nil
+		},
+	}
+
+	if err := command.Run([]string{"app", "--n", "3", "--f", "1.5", "--at", "2024-01-02T03:04:05Z", "--b", "x"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 3 || f != 1.5 || at.Year() != 2024 || !b {
+		t.Errorf("n = %d, f = %v, at = %v, b = %v", n, f, at, b)
+	}
+
+	if len(args) != 1 || args[0] != "x" {
+		t.Errorf("args = %v, want [x]", args)
+	}
+}
+
+func TestTimeOrInputLayouts(t *testing.T) {
+	var got time.Time
+
+	command := &Command{
+		Name:   "app",
+		Stdin:  strings.NewReader("2024-01-02\n"),
+		Stdout: io.Discard,
+		Options: []Option{
+			&TimeOption{Name: "date", Layouts: []string{"2006-01-02"}},
+		},
+		Action: func(context *Context) (err error) {
+			got, err = context.TimeOrInput("date")
+			return err
+		},
+	}
+
+	if err := command.Run([]string{"app"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Format("2006-01-02") != "2024-01-02" {
+		t.Errorf("date = %v, want 2024-01-02", got)
+	}
+}
+
+func TestUnknownErrors(t *testing.T) {
+	command := &Command{
+		Name:     "app",
+		Stdout:   io.Discard,
+		Options:  []Option{&BoolOption{Name: "verbose", Short: "v"}, &StringOption{Name: "x-ray"}},
+		Commands: []*Command{{Name: "deploy", Action: func(*Context) error { return nil }}},
+	}
+
+	var option *UnknownOptionError
+	if err := command.Run([]string{"app", "-V"}, nil); !errors.As(err, &option) {
+		t.Errorf("-V: got %v, want UnknownOptionError", err)
+	} else if strings.Join(option.Suggestions, ",") != "--verbose,-v" {
+		t.Errorf("-V: suggestions = %v", option.Suggestions)
+	}
+
+	var cmd *UnknownCommandError
+	if err := command.Run([]string{"app", "zzz"}, nil); !errors.As(err, &cmd) {
+		t.Errorf("zzz: got %v, want UnknownCommandError", err)
+	} else if len(cmd.Suggestions) != 0 {
+		t.Errorf("zzz: suggestions = %v", cmd.Suggestions)
+	}
+
+	var suggestions []string
+	custom := func(context *Context) error {
+		suggestions = context.Suggestions()
+		return nil
+	}
+	if err := command.Run([]string{"app", "deplo"}, custom); err != nil {
+		t.Errorf("deplo with default action: %v", err)
+	} else if strings.Join(suggestions, ",") != "deploy" {
+		t.Errorf("deplo: suggestions = %v, want [deploy]", suggestions)
+	}
+}
+
+func TestMissingValueToken(t *testing.T) {
+	command := &Command{
+		Name:         "app",
+		Abbreviation: true,
+		Options:      []Option{&StringOption{Name: "username", Short: "u"}},
+		Commands:     []*Command{{Name: "sub", Options: []Option{&StringOption{Name: "output", Short: "o"}}}},
+	}
+
+	for _, args := range [][]string{{"app", "--user"}, {"app", "sub", "-o"}} {
+		var missing *MissingValueError
+		if err := command.Run(args, nil); !errors.As(err, &missing) {
+			t.Errorf("%v: got %v, want MissingValueError", args, err)
+		} else if want := strings.Join(args[:len(args)-1], " "); missing.Command != want || missing.Token != args[len(args)-1] {
+			t.Errorf("%v: command = %q, token = %q", args, missing.Command, missing.Token)
+		}
+	}
+}
+
+type level struct {
+	names []string
+	cur   string
+}
+
+func (l *level) Set(s string) error {
+	for _, name := range l.names {
+		if name == s {
+			l.cur = s
+			return nil
+		}
+	}
+	return errors.New("bad level")
+}
+
+func (l *level) String() string {
+	return l.cur
+}
+
+type yes bool
+
+func (y *yes) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	*y = yes(v)
+	return err
+}
+
+func (y *yes) String() string {
+	return strconv.FormatBool(bool(*y))
+}
+
+func (y *yes) IsBoolFlag() bool {
+	return true
+}
+
+func TestValueOptionCopy(t *testing.T) {
+	newLevel := func() flag.Value {
+		return &level{names: []string{"debug", "info"}, cur: "info"}
+	}
+
+	var levels []string
+	var y bool
+	command := &Command{
+		Name: "app",
+		Options: []Option{
+			&ValueOption{Name: "level", Value: newLevel(), New: newLevel},
+			&ValueOption{Name: "yes", Value: new(yes)},
+		},
+		Action: func(context *Context) error {
+			levels = append(levels, context.Value("level").(*level).cur)
+			y = bool(*context.Value("yes").(*yes))
+			return nil
+		},
+	}
+
+	if err := command.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"app"}, {"app", "--level", "debug", "--yes"}} {
+		if err := command.Run(args, nil); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+	}
+
+	if strings.Join(levels, ",") != "info,debug" || !y {
+		t.Errorf("levels = %v, yes = %v", levels, y)
+	}
+
+	command.Options[0].(*ValueOption).New = nil
+	if err := command.Validate(); err == nil {
+		t.Error("Validate() = nil, want an error for a value that cannot be copied")
+	}
+}
+
+func TestPersistentOptionsAccumulate(t *testing.T) {
+	var verbose int
+	var tags []string
+
+	command := &Command{
+		Name: "app",
+		Options: []Option{
+			&CountOption{Name: "verbose", Short: "v", Persistent: true},
+			&StringSliceOption{Name: "tag", Short: "t", Persistent: true},
+		},
+		Commands: []*Command{{
+			Name: "remote",
+			Commands: []*Command{{
+				Name: "add",
+				Action: func(context *Context) error {
+					verbose = context.Count("verbose")
+					tags = context.StringSlice("tag")
+					return nil
+				},
+			}},
+		}},
+	}
+
+	if err := command.Run([]string{"app", "-v", "-t", "a", "remote", "add", "-vv", "-t", "b"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if verbose != 3 || strings.Join(tags, ",") != "a,b" {
+		t.Errorf("verbose = %d, tags = %v, want 3, [a b]", verbose, tags)
+	}
+
+	t.Setenv("APP_TAG", "env")
+	command.EnvPrefix = "APP_"
+
+	if err := command.Run([]string{"app", "remote", "add"}, nil); err != nil || strings.Join(tags, ",") != "env" {
+		t.Fatalf("tags = %v, %v, want [env]", tags, err)
+	}
+
+	if err := command.Run([]string{"app", "remote", "add", "-t", "b"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(tags, ",") != "b" {
+		t.Errorf("tags = %v, want the command line to replace the environment", tags)
+	}
+}
+
+func TestConfigDateWithLayout(t *testing.T) {
+	files := map[string]string{
+		"app.yaml": "date: 2024-01-02\n",
+		"app.toml": "date = 2024-01-02\n",
+	}
+
+	for file, content := range files {
+		path := filepath.Join(t.TempDir(), file)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		var got time.Time
+		command := &Command{
+			Name:       "app",
+			ConfigFile: "app",
+			Options:    []Option{&TimeOption{Name: "date", Layouts: []string{"2006-01-02"}}},
+			Action: func(context *Context) error {
+				got = context.Time("date")
+				return nil
+			},
+		}
+
+		if err := command.Run([]string{"app", "--config", path}, nil); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+
+		if got.Format("2006-01-02") != "2024-01-02" {
+			t.Errorf("%s: date = %v, want 2024-01-02", file, got)
+		}
+	}
+}
+
+func TestSliceNoSplit(t *testing.T) {
+	var headers []string
+
+	command := &Command{
+		Name:    "app",
+		Options: []Option{&StringSliceOption{Name: "header", Short: "H", NoSplit: true}},
+		Action: func(context *Context) error {
+			headers = context.StringSlice("header")
+			return nil
+		},
+	}
+
+	args := []string{"app", "-H", "Accept: text/html, application/json", "-H", "X-A: 1"}
+	if err := command.Run(args, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(headers) != 2 || headers[0] != "Accept: text/html, application/json" {
+		t.Errorf("headers = %q", headers)
+	}
+}
+
+func TestMapNoSplit(t *testing.T) {
+	var labels map[string]string
+
+	command := &Command{
+		Name:    "app",
+		Options: []Option{&StringMapOption{Name: "label", NoSplit: true}},
+		Action: func(context *Context) error {
+			labels = context.StringMap("label")
+			return nil
+		},
+	}
+
+	if err := command.Run([]string{"app", "--label", "desc=a,b", "--label", "x=1"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(labels) != 2 || labels["desc"] != "a,b" {
+		t.Errorf("labels = %v", labels)
+	}
+}
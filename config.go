@@ -0,0 +1,263 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+var configExts = []string{".json", ".toml", ".yaml", ".yml", ".ini"}
+
+func (context *Context) readConfig(values map[string]interface{}) error {
+	if context.parent != nil {
+		context.config, _ = context.parent.config[context.command.Name].(map[string]interface{})
+		context.configFile = context.parent.configFile
+		return nil
+	}
+
+	if context.command.ConfigFile == "" {
+		return nil
+	}
+
+	path, _ := values["config"].(string)
+	if path == "" {
+		dir, err := context.UserConfigDir()
+		if err != nil {
+			return nil
+		}
+
+		path, err = findConfigFile(dir, context.command.ConfigFile)
+		if err != nil {
+			return err
+		}
+
+		if path == "" {
+			return nil
+		}
+	}
+
+	config, err := loadConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	if keys := context.command.unknownConfigKeys(config, ""); len(keys) > 0 {
+		return errors.New("unknown config keys in " + path + ": " + strings.Join(keys, ", "))
+	}
+
+	context.config = config
+	context.configFile = path
+
+	return nil
+}
+
+func (context *Context) configKey(option Option) string {
+	key := optionKey(option)
+	for c := context; c.parent != nil; c = c.parent {
+		key = c.command.Name + "." + key
+	}
+	return key
+}
+
+func findConfigFile(dir string, name string) (string, error) {
+	if filepath.Ext(name) != "" {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				return "", nil
+			}
+			return "", err
+		}
+		return path, nil
+	}
+
+	for _, ext := range configExts {
+		path := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+		return path, nil
+	}
+
+	return "", nil
+}
+
+func loadConfigFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := map[string]interface{}{}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &config)
+	case ".toml":
+		err = toml.Unmarshal(data, &config)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &config)
+	case ".ini":
+		config, err = parseINI(string(data))
+	default:
+		return nil, errors.New("unsupported config file: " + path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+func parseINI(text string) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	table := config
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || line[0] == ';' || line[0] == '#':
+			continue
+
+		case line[0] == '[':
+			if line[len(line)-1] != ']' {
+				return nil, errors.New("line " + strconv.Itoa(n) + ": invalid section: " + line)
+			}
+
+			table = config
+			for _, name := range strings.Split(line[1:len(line)-1], ".") {
+				name = strings.TrimSpace(name)
+
+				sub, ok := table[name].(map[string]interface{})
+				if !ok {
+					sub = map[string]interface{}{}
+					table[name] = sub
+				}
+				table = sub
+			}
+
+		default:
+			i := strings.Index(line, "=")
+			if i < 0 {
+				return nil, errors.New("line " + strconv.Itoa(n) + ": missing '=': " + line)
+			}
+
+			key := strings.TrimSpace(line[:i])
+			value := strings.TrimSpace(line[i+1:])
+			if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+				value = value[1 : len(value)-1]
+			}
+
+			table[key] = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func (command *Command) unknownConfigKeys(config map[string]interface{}, prefix string) []string {
+	keys := []string{}
+
+KEYS:
+	for key, value := range config {
+		for _, option := range command.Options {
+			if optionKey(option) == key && !command.isBuiltinOption(option) {
+				continue KEYS
+			}
+		}
+
+		if table, ok := value.(map[string]interface{}); ok {
+			for _, subcommand := range command.Commands {
+				if subcommand.Name == key {
+					keys = append(keys, subcommand.unknownConfigKeys(table, prefix+key+".")...)
+					continue KEYS
+				}
+			}
+		}
+
+		keys = append(keys, prefix+key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func configTimeLayout(option Option) string {
+	if o, ok := option.(*TimeOption); ok {
+		return o.layouts()[0]
+	}
+	return time.RFC3339Nano
+}
+
+func configValues(value interface{}, layout string) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case bool:
+		return []string{strconv.FormatBool(v)}, nil
+	case int:
+		return []string{strconv.Itoa(v)}, nil
+	case int64:
+		return []string{strconv.FormatInt(v, 10)}, nil
+	case uint64:
+		return []string{strconv.FormatUint(v, 10)}, nil
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}, nil
+	case time.Time:
+		return []string{v.Format(layout)}, nil
+	case map[string]interface{}:
+		values := []string{}
+		for key, elem := range v {
+			vs, err := configValues(elem, layout)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(vs) != 1 {
+				return nil, errors.New("invalid value for key: " + key)
+			}
+
+			values = append(values, key+"="+vs[0])
+		}
+		sort.Strings(values)
+		return values, nil
+	case []interface{}:
+		values := []string{}
+		for _, elem := range v {
+			if _, ok := elem.([]interface{}); ok {
+				return nil, errors.New("nested list is not supported")
+			}
+
+			vs, err := configValues(elem, layout)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, vs...)
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("unsupported value: %v", value)
+	}
+}
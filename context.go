@@ -1,27 +1,74 @@
 package cli
 
 import (
+	"flag"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/thamaji/tablewriter"
 )
 
+type ValueSource int
+
+const (
+	SourceNone ValueSource = iota
+	SourceDefault
+	SourceConfigFile
+	SourceEnv
+	SourceCommandLine
+	SourcePrompt
+)
+
+func (source ValueSource) String() string {
+	switch source {
+	case SourceDefault:
+		return "default"
+	case SourceConfigFile:
+		return "config file"
+	case SourceEnv:
+		return "environment"
+	case SourceCommandLine:
+		return "command line"
+	case SourcePrompt:
+		return "prompt"
+	default:
+		return "none"
+	}
+}
+
+type source struct {
+	source ValueSource
+	raw    string
+}
+
 type Context struct {
 	parent *Context
 
-	command *Command
-	options map[string]interface{}
-	args    []string
+	command  *Command
+	flags    []Option
+	options  map[string]interface{}
+	defaults map[string]interface{}
+	sources  map[string]source
+	args     []string
+
+	suggestions []string
+
+	config     map[string]interface{}
+	configFile string
 }
 
 func (context Context) Args() []string {
 	return context.args
 }
 
+func (context Context) Suggestions() []string {
+	return context.suggestions
+}
+
 func (context Context) UserConfigDir() (string, error) {
 	dir, err := os.UserConfigDir()
 	if err != nil {
@@ -40,380 +87,670 @@ func (context Context) UserCacheDir() (string, error) {
 	return filepath.Join(dir, context.Name()), nil
 }
 
-func (context Context) IsSet(name string) bool {
-	if _, ok := context.options[name]; ok {
-		return true
+func (context Context) Stdin() io.Reader {
+	if context.command.Stdin != nil {
+		return context.command.Stdin
 	}
 
 	if context.parent == nil {
-		return false
+		return os.Stdin
 	}
 
-	return context.parent.IsSet(name)
+	return context.parent.Stdin()
 }
 
-func (context Context) Bool(name string) bool {
-	v, ok := context.options[name]
-	if ok {
-		return v.(bool)
+func (context Context) Stdout() io.Writer {
+	if context.command.Stdout != nil {
+		return context.command.Stdout
 	}
 
 	if context.parent == nil {
-		return false
+		return os.Stdout
 	}
 
-	return context.parent.Bool(name)
+	return context.parent.Stdout()
 }
 
-func (context Context) BoolOr(name string, value bool) bool {
-	v, ok := context.options[name]
-	if ok {
-		return v.(bool)
+func (context Context) Stderr() io.Writer {
+	if context.command.Stderr != nil {
+		return context.command.Stderr
 	}
 
 	if context.parent == nil {
-		return value
+		return os.Stderr
 	}
 
-	return context.parent.BoolOr(name, value)
+	return context.parent.Stderr()
 }
 
-func (context Context) BoolOrInput(name string) (bool, error) {
-	v, ok := context.options[name]
-	if ok {
-		return v.(bool), nil
+func (context Context) IsSet(name string) bool {
+	if _, ok := context.options[name]; ok {
+		return true
 	}
 
 	if context.parent == nil {
-		return ReadInputBool(name)
+		return false
 	}
 
-	return context.parent.BoolOrInput(name)
+	return context.parent.IsSet(name)
 }
 
-func (context Context) BoolOrPassword(name string) (bool, error) {
-	v, ok := context.options[name]
-	if ok {
-		return v.(bool), nil
+func (context Context) HasValue(name string) bool {
+	if _, ok := context.lookup(name); ok {
+		return true
 	}
 
 	if context.parent == nil {
-		return ReadPasswordBool(name)
+		return false
 	}
 
-	return context.parent.BoolOrInput(name)
+	return context.parent.HasValue(name)
 }
 
-func (context Context) String(name string) string {
-	v, ok := context.options[name]
-	if ok {
-		return v.(string)
+func (context Context) lookup(name string) (interface{}, bool) {
+	if v, ok := context.options[name]; ok {
+		return v, true
 	}
 
-	if context.parent == nil {
-		return ""
-	}
-
-	return context.parent.String(name)
+	v, ok := context.defaults[name]
+	return v, ok
 }
 
-func (context Context) StringOr(name string, value string) string {
-	v, ok := context.options[name]
-	if ok {
-		return v.(string)
+func (context Context) Source(name string) (ValueSource, string) {
+	if s, ok := context.sources[name]; ok {
+		return s.source, s.raw
 	}
 
 	if context.parent == nil {
-		return value
+		return SourceNone, ""
 	}
 
-	return context.parent.StringOr(name, value)
+	return context.parent.Source(name)
 }
 
-func (context Context) StringOrInput(name string) (string, error) {
-	v, ok := context.options[name]
-	if ok {
-		return v.(string), nil
+func Lookup[T any](context *Context, name string) (T, error) {
+	var mismatch interface{}
+	found := false
+
+	for c := context; c != nil; c = c.parent {
+		v, ok := c.lookup(name)
+		if !ok {
+			continue
+		}
+
+		if t, ok := v.(T); ok {
+			return t, nil
+		}
+
+		if !found {
+			mismatch, found = v, true
+		}
 	}
 
-	if context.parent == nil {
-		return ReadInputString(name)
+	var zero T
+	if found {
+		return zero, &TypeMismatchError{Name: name, Type: typeName[T](), Value: mismatch}
 	}
 
-	return context.parent.StringOrInput(name)
+	return zero, &NoValueError{Name: name}
 }
 
-func (context Context) StringOrPassword(name string) (string, error) {
-	v, ok := context.options[name]
-	if ok {
-		return v.(string), nil
-	}
+func Get[T any](context *Context, name string) T {
+	v, _ := Lookup[T](context, name)
+	return v
+}
 
-	if context.parent == nil {
-		return ReadPasswordString(name)
+func GetOr[T any](context *Context, name string, value T) T {
+	v, err := Lookup[T](context, name)
+	if err != nil {
+		return value
 	}
 
-	return context.parent.StringOrPassword(name)
+	return v
 }
 
-func (context Context) Int(name string) int {
-	v, ok := context.options[name]
-	if ok {
-		return v.(int)
+func GetOrInput[T any](context *Context, name string) (T, error) {
+	v, err := Lookup[T](context, name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return 0
+	ans, err := readInput(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return v, err
+	}
+
+	v, err = parser[T](context, name)(ans)
+	if err != nil {
+		return v, err
 	}
 
-	return context.parent.Int(name)
+	context.root().setPrompted(name, v, ans)
+	return v, nil
 }
 
-func (context Context) IntOr(name string, value int) int {
-	v, ok := context.options[name]
-	if ok {
-		return v.(int)
+func parser[T any](context *Context, name string) func(string) (T, error) {
+	for c := context; c != nil; c = c.parent {
+		for _, option := range c.flags {
+			if opt, ok := option.(*Opt[T]); ok && opt.Key() == name {
+				return opt.parse
+			}
+		}
 	}
+	return parseValue[T]
+}
 
-	if context.parent == nil {
-		return value
+func (context *Context) timeLayouts(name string) []string {
+	for c := context; c != nil; c = c.parent {
+		for _, option := range c.flags {
+			if opt, ok := option.(*TimeOption); ok && opt.Key() == name {
+				return opt.layouts()
+			}
+		}
 	}
+	return []string{time.RFC3339}
+}
 
-	return context.parent.IntOr(name, value)
+func (context *Context) root() *Context {
+	for context.parent != nil {
+		context = context.parent
+	}
+	return context
 }
 
-func (context Context) IntOrInput(name string) (int, error) {
-	v, ok := context.options[name]
-	if ok {
-		return v.(int), nil
+func (context Context) setPrompted(name string, value interface{}, raw string) {
+	context.options[name] = value
+	context.sources[name] = source{SourcePrompt, raw}
+}
+
+func (context Context) LookupBool(name string) (bool, error) {
+	return Lookup[bool](&context, name)
+}
+
+func (context Context) Bool(name string) bool {
+	return Get[bool](&context, name)
+}
+
+func (context Context) BoolOr(name string, value bool) bool {
+	return GetOr[bool](&context, name, value)
+}
+
+func (context Context) BoolOrInput(name string) (bool, error) {
+	v, err := context.LookupBool(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return ReadInputInt(name)
+	v, err = readInputBool(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return false, err
 	}
 
-	return context.parent.IntOrInput(name)
+	context.root().setPrompted(name, v, fmt.Sprint(v))
+	return v, nil
 }
 
-func (context Context) IntOrPassword(name string) (int, error) {
-	v, ok := context.options[name]
-	if ok {
-		return v.(int), nil
+func (context Context) BoolOrPassword(name string) (bool, error) {
+	v, err := context.LookupBool(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return ReadPasswordInt(name)
+	v, err = readPasswordBool(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return false, err
 	}
 
-	return context.parent.IntOrPassword(name)
+	context.root().setPrompted(name, v, "")
+	return v, nil
 }
 
-func (context Context) Int32(name string) int32 {
-	v, ok := context.options[name]
-	if ok {
-		return v.(int32)
+func (context Context) LookupString(name string) (string, error) {
+	return Lookup[string](&context, name)
+}
+
+func (context Context) String(name string) string {
+	return Get[string](&context, name)
+}
+
+func (context Context) StringOr(name string, value string) string {
+	return GetOr[string](&context, name, value)
+}
+
+func (context Context) StringOrInput(name string) (string, error) {
+	v, err := context.LookupString(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return 0
+	v, err = readInputString(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return "", err
 	}
 
-	return context.parent.Int32(name)
+	context.root().setPrompted(name, v, fmt.Sprint(v))
+	return v, nil
 }
 
-func (context Context) Int32Or(name string, value int32) int32 {
-	v, ok := context.options[name]
-	if ok {
-		return v.(int32)
+func (context Context) StringOrPassword(name string) (string, error) {
+	v, err := context.LookupString(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return value
+	v, err = readPasswordString(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return "", err
 	}
 
-	return context.parent.Int32Or(name, value)
+	context.root().setPrompted(name, v, "")
+	return v, nil
 }
 
-func (context Context) Int32OrInput(name string) (int32, error) {
-	v, ok := context.options[name]
-	if ok {
-		return v.(int32), nil
+func (context Context) LookupInt(name string) (int, error) {
+	return Lookup[int](&context, name)
+}
+
+func (context Context) Int(name string) int {
+	return Get[int](&context, name)
+}
+
+func (context Context) IntOr(name string, value int) int {
+	return GetOr[int](&context, name, value)
+}
+
+func (context Context) IntOrInput(name string) (int, error) {
+	v, err := context.LookupInt(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return ReadInputInt32(name)
+	v, err = readInputInt(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return 0, err
 	}
 
-	return context.parent.Int32OrInput(name)
+	context.root().setPrompted(name, v, fmt.Sprint(v))
+	return v, nil
 }
 
-func (context Context) Int32OrPassword(name string) (int32, error) {
-	v, ok := context.options[name]
-	if ok {
-		return v.(int32), nil
+func (context Context) IntOrPassword(name string) (int, error) {
+	v, err := context.LookupInt(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return ReadPasswordInt32(name)
+	v, err = readPasswordInt(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return 0, err
 	}
 
-	return context.parent.Int32OrPassword(name)
+	context.root().setPrompted(name, v, "")
+	return v, nil
 }
 
-func (context Context) Int64(name string) int64 {
-	v, ok := context.options[name]
-	if ok {
-		return v.(int64)
+func (context Context) Count(name string) int {
+	return Get[int](&context, name)
+}
+
+func (context Context) LookupInt32(name string) (int32, error) {
+	return Lookup[int32](&context, name)
+}
+
+func (context Context) Int32(name string) int32 {
+	return Get[int32](&context, name)
+}
+
+func (context Context) Int32Or(name string, value int32) int32 {
+	return GetOr[int32](&context, name, value)
+}
+
+func (context Context) Int32OrInput(name string) (int32, error) {
+	v, err := context.LookupInt32(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return 0
+	v, err = readInputInt32(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return 0, err
 	}
 
-	return context.parent.Int64(name)
+	context.root().setPrompted(name, v, fmt.Sprint(v))
+	return v, nil
 }
 
-func (context Context) Int64Or(name string, value int64) int64 {
-	v, ok := context.options[name]
-	if ok {
-		return v.(int64)
+func (context Context) Int32OrPassword(name string) (int32, error) {
+	v, err := context.LookupInt32(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return value
+	v, err = readPasswordInt32(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return 0, err
 	}
 
-	return context.parent.Int64Or(name, value)
+	context.root().setPrompted(name, v, "")
+	return v, nil
+}
+
+func (context Context) LookupInt64(name string) (int64, error) {
+	return Lookup[int64](&context, name)
+}
+
+func (context Context) Int64(name string) int64 {
+	return Get[int64](&context, name)
+}
+
+func (context Context) Int64Or(name string, value int64) int64 {
+	return GetOr[int64](&context, name, value)
 }
 
 func (context Context) Int64OrInput(name string) (int64, error) {
-	v, ok := context.options[name]
-	if ok {
-		return v.(int64), nil
+	v, err := context.LookupInt64(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return ReadInputInt64(name)
+	v, err = readInputInt64(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return 0, err
 	}
 
-	return context.parent.Int64OrInput(name)
+	context.root().setPrompted(name, v, fmt.Sprint(v))
+	return v, nil
 }
 
 func (context Context) Int64OrPassword(name string) (int64, error) {
-	v, ok := context.options[name]
-	if ok {
-		return v.(int64), nil
+	v, err := context.LookupInt64(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return ReadPasswordInt64(name)
+	v, err = readPasswordInt64(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return 0, err
 	}
 
-	return context.parent.Int64OrPassword(name)
+	context.root().setPrompted(name, v, "")
+	return v, nil
+}
+
+func (context Context) LookupFloat32(name string) (float32, error) {
+	return Lookup[float32](&context, name)
 }
 
 func (context Context) Float32(name string) float32 {
-	v, ok := context.options[name]
-	if ok {
-		return v.(float32)
+	return Get[float32](&context, name)
+}
+
+func (context Context) Float32Or(name string, value float32) float32 {
+	return GetOr[float32](&context, name, value)
+}
+
+func (context Context) Float32OrInput(name string) (float32, error) {
+	v, err := context.LookupFloat32(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return 0
+	v, err = readInputFloat32(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return 0, err
 	}
 
-	return context.parent.Float32(name)
+	context.root().setPrompted(name, v, fmt.Sprint(v))
+	return v, nil
 }
 
-func (context Context) Float32Or(name string, value float32) float32 {
-	v, ok := context.options[name]
-	if ok {
-		return v.(float32)
+func (context Context) Float32OrPassword(name string) (float32, error) {
+	v, err := context.LookupFloat32(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return value
+	v, err = readPasswordFloat32(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return 0, err
 	}
 
-	return context.parent.Float32Or(name, value)
+	context.root().setPrompted(name, v, "")
+	return v, nil
 }
 
-func (context Context) Float32OrInput(name string) (float32, error) {
-	v, ok := context.options[name]
-	if ok {
-		return v.(float32), nil
+func (context Context) LookupFloat64(name string) (float64, error) {
+	return Lookup[float64](&context, name)
+}
+
+func (context Context) Float64(name string) float64 {
+	return Get[float64](&context, name)
+}
+
+func (context Context) Float64Or(name string, value float64) float64 {
+	return GetOr[float64](&context, name, value)
+}
+
+func (context Context) Float64OrInput(name string) (float64, error) {
+	v, err := context.LookupFloat64(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return ReadInputFloat32(name)
+	v, err = readInputFloat64(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return 0, err
 	}
 
-	return context.parent.Float32OrInput(name)
+	context.root().setPrompted(name, v, fmt.Sprint(v))
+	return v, nil
 }
 
-func (context Context) Float32OrPassword(name string) (float32, error) {
-	v, ok := context.options[name]
-	if ok {
-		return v.(float32), nil
+func (context Context) Float64OrPassword(name string) (float64, error) {
+	v, err := context.LookupFloat64(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return ReadPasswordFloat32(name)
+	v, err = readPasswordFloat64(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return 0, err
 	}
 
-	return context.parent.Float32OrPassword(name)
+	context.root().setPrompted(name, v, "")
+	return v, nil
 }
 
-func (context Context) Float64(name string) float64 {
-	v, ok := context.options[name]
-	if ok {
-		return v.(float64)
+func (context Context) LookupDuration(name string) (time.Duration, error) {
+	return Lookup[time.Duration](&context, name)
+}
+
+func (context Context) Duration(name string) time.Duration {
+	return Get[time.Duration](&context, name)
+}
+
+func (context Context) DurationOr(name string, value time.Duration) time.Duration {
+	return GetOr[time.Duration](&context, name, value)
+}
+
+func (context Context) DurationOrInput(name string) (time.Duration, error) {
+	v, err := context.LookupDuration(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return 0
+	v, err = readInputDuration(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return 0, err
 	}
 
-	return context.parent.Float64(name)
+	context.root().setPrompted(name, v, v.String())
+	return v, nil
 }
 
-func (context Context) Float64Or(name string, value float64) float64 {
-	v, ok := context.options[name]
-	if ok {
-		return v.(float64)
+func (context Context) LookupTime(name string) (time.Time, error) {
+	return Lookup[time.Time](&context, name)
+}
+
+func (context Context) Time(name string) time.Time {
+	return Get[time.Time](&context, name)
+}
+
+func (context Context) TimeOr(name string, value time.Time) time.Time {
+	return GetOr[time.Time](&context, name, value)
+}
+
+func (context Context) TimeOrInput(name string) (time.Time, error) {
+	v, err := context.LookupTime(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return value
+	layouts := context.timeLayouts(name)
+
+	v, err = readInputTime(context.Stdin(), context.Stdout(), name, layouts...)
+	if err != nil {
+		return time.Time{}, err
 	}
 
-	return context.parent.Float64Or(name, value)
+	context.root().setPrompted(name, v, v.Format(layouts[0]))
+	return v, nil
 }
 
-func (context Context) Float64OrInput(name string) (float64, error) {
-	v, ok := context.options[name]
-	if ok {
-		return v.(float64), nil
+func (context Context) LookupByteSize(name string) (ByteSize, error) {
+	return Lookup[ByteSize](&context, name)
+}
+
+func (context Context) ByteSize(name string) ByteSize {
+	return Get[ByteSize](&context, name)
+}
+
+func (context Context) ByteSizeOr(name string, value ByteSize) ByteSize {
+	return GetOr[ByteSize](&context, name, value)
+}
+
+func (context Context) ByteSizeOrInput(name string) (ByteSize, error) {
+	v, err := context.LookupByteSize(name)
+	if _, ok := err.(*NoValueError); !ok {
+		return v, err
 	}
 
-	if context.parent == nil {
-		return ReadInputFloat64(name)
+	v, err = readInputByteSize(context.Stdin(), context.Stdout(), name)
+	if err != nil {
+		return 0, err
 	}
 
-	return context.parent.Float64OrInput(name)
+	context.root().setPrompted(name, v, v.String())
+	return v, nil
 }
 
-func (context Context) Float64OrPassword(name string) (float64, error) {
-	v, ok := context.options[name]
-	if ok {
-		return v.(float64), nil
+func (context Context) LookupStringSlice(name string) ([]string, error) {
+	return Lookup[[]string](&context, name)
+}
+
+func (context Context) StringSlice(name string) []string {
+	return Get[[]string](&context, name)
+}
+
+func (context Context) StringSliceOr(name string, value []string) []string {
+	return GetOr[[]string](&context, name, value)
+}
+
+func (context Context) LookupIntSlice(name string) ([]int, error) {
+	return Lookup[[]int](&context, name)
+}
+
+func (context Context) IntSlice(name string) []int {
+	return Get[[]int](&context, name)
+}
+
+func (context Context) IntSliceOr(name string, value []int) []int {
+	return GetOr[[]int](&context, name, value)
+}
+
+func (context Context) LookupInt64Slice(name string) ([]int64, error) {
+	return Lookup[[]int64](&context, name)
+}
+
+func (context Context) Int64Slice(name string) []int64 {
+	return Get[[]int64](&context, name)
+}
+
+func (context Context) Int64SliceOr(name string, value []int64) []int64 {
+	return GetOr[[]int64](&context, name, value)
+}
+
+func (context Context) LookupFloat64Slice(name string) ([]float64, error) {
+	return Lookup[[]float64](&context, name)
+}
+
+func (context Context) Float64Slice(name string) []float64 {
+	return Get[[]float64](&context, name)
+}
+
+func (context Context) Float64SliceOr(name string, value []float64) []float64 {
+	return GetOr[[]float64](&context, name, value)
+}
+
+func (context Context) LookupStringMap(name string) (map[string]string, error) {
+	return Lookup[map[string]string](&context, name)
+}
+
+func (context Context) StringMap(name string) map[string]string {
+	return Get[map[string]string](&context, name)
+}
+
+func (context Context) StringMapOr(name string, value map[string]string) map[string]string {
+	return GetOr[map[string]string](&context, name, value)
+}
+
+func (context Context) LookupIntMap(name string) (map[string]int, error) {
+	return Lookup[map[string]int](&context, name)
+}
+
+func (context Context) IntMap(name string) map[string]int {
+	return Get[map[string]int](&context, name)
+}
+
+func (context Context) IntMapOr(name string, value map[string]int) map[string]int {
+	return GetOr[map[string]int](&context, name, value)
+}
+
+func (context Context) LookupInt64Map(name string) (map[string]int64, error) {
+	return Lookup[map[string]int64](&context, name)
+}
+
+func (context Context) Int64Map(name string) map[string]int64 {
+	return Get[map[string]int64](&context, name)
+}
+
+func (context Context) Int64MapOr(name string, value map[string]int64) map[string]int64 {
+	return GetOr[map[string]int64](&context, name, value)
+}
+
+func (context Context) LookupFloat64Map(name string) (map[string]float64, error) {
+	return Lookup[map[string]float64](&context, name)
+}
+
+func (context Context) Float64Map(name string) map[string]float64 {
+	return Get[map[string]float64](&context, name)
+}
+
+func (context Context) Float64MapOr(name string, value map[string]float64) map[string]float64 {
+	return GetOr[map[string]float64](&context, name, value)
+}
+
+func (context Context) Value(name string) interface{} {
+	v, err := Lookup[flag.Value](&context, name)
+	if err != nil {
+		return nil
 	}
 
-	if context.parent == nil {
-		return ReadPasswordFloat64(name)
+	if t, ok := v.(*textValue); ok {
+		return t.v
 	}
 
-	return context.parent.Float64OrPassword(name)
+	return v
 }
 
 func (context *Context) Name() string {
@@ -423,6 +760,58 @@ func (context *Context) Name() string {
 	return context.parent.Name() + " " + context.command.Name
 }
 
+func (context *Context) envKeywords(option Option) []string {
+	keywords := optionEnvKeywords(option)
+
+	if keyword := context.envPrefixKeyword(option); keyword != "" {
+		keywords = append(keywords, keyword)
+	}
+
+	return keywords
+}
+
+func (context *Context) envPrefixKeyword(option Option) string {
+	if context.command.isBuiltinOption(option) || optionKey(option) == "" {
+		return ""
+	}
+
+	names := []string{optionKey(option)}
+	root := context
+	for root.parent != nil {
+		names = append([]string{root.command.Name}, names...)
+		root = root.parent
+	}
+
+	if root.command.EnvPrefix == "" {
+		return ""
+	}
+
+	name := strings.Join(names, "_")
+	name = strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+
+	return root.command.EnvPrefix + name
+}
+
+func (context *Context) persistentOptions() []Option {
+	options := []Option{}
+	keys := map[string]bool{}
+
+	for _, option := range context.flags {
+		keys[optionKey(option)] = true
+	}
+
+	for c := context.parent; c != nil; c = c.parent {
+		for _, option := range c.flags {
+			if isPersistent(option) && !keys[optionKey(option)] {
+				options = append(options, option)
+				keys[optionKey(option)] = true
+			}
+		}
+	}
+
+	return options
+}
+
 func (context *Context) ShowHelp(out io.Writer) error {
 	fmt.Fprintln(out, "NAME:")
 	name := context.Name()
@@ -437,7 +826,7 @@ func (context *Context) ShowHelp(out io.Writer) error {
 	fmt.Fprintln(out, "USAGE:")
 	usage := context.Name()
 
-	if len(context.command.Options) > 0 {
+	if len(context.flags) > 0 {
 		usage += " [OPTIONS]"
 	}
 
@@ -462,17 +851,45 @@ func (context *Context) ShowHelp(out io.Writer) error {
 		tw.Flush()
 	}
 
-	if len(context.command.Options) > 0 {
+	if len(context.flags) > 0 {
 		fmt.Fprintln(out)
 		fmt.Fprintln(out, "OPTIONS:")
 		tw := tablewriter.New(out)
-		for _, option := range context.command.Options {
+		for _, option := range context.flags {
+			help := option.Help()
+			tw.Add(" ", help[0], help[1])
+		}
+		tw.Flush()
+	}
+
+	if persistentOptions := context.persistentOptions(); len(persistentOptions) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "GLOBAL OPTIONS:")
+		tw := tablewriter.New(out)
+		for _, option := range persistentOptions {
 			help := option.Help()
 			tw.Add(" ", help[0], help[1])
 		}
 		tw.Flush()
 	}
 
+	environments := [][2]string{}
+	for _, option := range context.flags {
+		if keyword := context.envPrefixKeyword(option); keyword != "" {
+			environments = append(environments, [2]string{keyword, option.Help()[0]})
+		}
+	}
+
+	if len(environments) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "ENVIRONMENT:")
+		tw := tablewriter.New(out)
+		for _, environment := range environments {
+			tw.Add(" ", environment[0], environment[1])
+		}
+		tw.Flush()
+	}
+
 	if context.command.Copyright != "" {
 		fmt.Fprintln(out)
 		fmt.Fprintln(out, "COPYRIGHT:")
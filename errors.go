@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidArguments = errors.New("invalid arguments")
+
+type UnknownOptionError struct {
+	Command     string
+	Token       string
+	Suggestions []string
+}
+
+func (err *UnknownOptionError) Error() string {
+	return "unknown option: " + err.Token + didYouMean(err.Suggestions)
+}
+
+func (err *UnknownOptionError) Is(target error) bool {
+	return target == ErrInvalidArguments
+}
+
+type AmbiguousOptionError struct {
+	Command    string
+	Token      string
+	Candidates []string
+}
+
+func (err *AmbiguousOptionError) Error() string {
+	return "ambiguous option: " + err.Token + " (" + strings.Join(err.Candidates, ", ") + ")"
+}
+
+func (err *AmbiguousOptionError) Is(target error) bool {
+	return target == ErrInvalidArguments
+}
+
+type UnknownCommandError struct {
+	Command     string
+	Token       string
+	Suggestions []string
+}
+
+func (err *UnknownCommandError) Error() string {
+	return "unknown command: " + err.Token + didYouMean(err.Suggestions)
+}
+
+func (err *UnknownCommandError) Is(target error) bool {
+	return target == ErrInvalidArguments
+}
+
+type AmbiguousCommandError struct {
+	Command    string
+	Token      string
+	Candidates []string
+}
+
+func (err *AmbiguousCommandError) Error() string {
+	return "ambiguous command: " + err.Token + " (" + strings.Join(err.Candidates, ", ") + ")"
+}
+
+func (err *AmbiguousCommandError) Is(target error) bool {
+	return target == ErrInvalidArguments
+}
+
+type MissingValueError struct {
+	Command string
+	Option  Option
+	Token   string
+}
+
+func (err *MissingValueError) Error() string {
+	if err.Token == "" {
+		return "missing required value: " + err.Option.Help()[0]
+	}
+	return "missing required value for " + err.Token + ": " + err.Option.Help()[0]
+}
+
+func (err *MissingValueError) Is(target error) bool {
+	return target == ErrInvalidArguments
+}
+
+type InvalidValueError struct {
+	Command string
+	Option  Option
+	Token   string
+	Err     error
+}
+
+func (err *InvalidValueError) Error() string {
+	return "invalid value " + strconv.Quote(err.Token) + " for " + err.Option.Help()[0] + ": " + err.Err.Error()
+}
+
+func (err *InvalidValueError) Unwrap() error {
+	return err.Err
+}
+
+func (err *InvalidValueError) Is(target error) bool {
+	return target == ErrInvalidArguments
+}
+
+type RequiredOptionsError struct {
+	Command string
+	Options []Option
+}
+
+func (err *RequiredOptionsError) Error() string {
+	names := make([]string, 0, len(err.Options))
+	for _, option := range err.Options {
+		names = append(names, "--"+optionKey(option))
+	}
+	return "missing required options: " + strings.Join(names, ", ")
+}
+
+func (err *RequiredOptionsError) Is(target error) bool {
+	return target == ErrInvalidArguments
+}
+
+type NoValueError struct {
+	Name string
+}
+
+func (err *NoValueError) Error() string {
+	return "no value for option: " + err.Name
+}
+
+type TypeMismatchError struct {
+	Name  string
+	Type  string
+	Value interface{}
+}
+
+func (err *TypeMismatchError) Error() string {
+	return fmt.Sprintf("option %s is %T, not %s", err.Name, err.Value, err.Type)
+}
+
+type ValidationError struct {
+	Problems []string
+}
+
+func (err *ValidationError) Error() string {
+	return "invalid command definition: " + strings.Join(err.Problems, "; ")
+}
+
+func invalidValue(option Option, token string, err error) error {
+	var numError *strconv.NumError
+	if errors.As(err, &numError) {
+		err = numError.Err
+	}
+	return &InvalidValueError{Option: option, Token: token, Err: err}
+}
+
+func (context *Context) optionError(err error, token string) error {
+	var missing *MissingValueError
+	if errors.As(err, &missing) {
+		if missing.Command == "" {
+			missing.Command = context.Name()
+		}
+		if missing.Token == "" {
+			missing.Token = token
+		}
+	}
+
+	var invalid *InvalidValueError
+	if errors.As(err, &invalid) && invalid.Command == "" {
+		invalid.Command = context.Name()
+	}
+
+	return err
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+type ExitCoder interface {
+	error
+	ExitCode() int
+}
+
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (err *ExitError) Error() string {
+	if err.Err == nil {
+		return ""
+	}
+	return err.Err.Error()
+}
+
+func (err *ExitError) Unwrap() error {
+	return err.Err
+}
+
+func (err *ExitError) ExitCode() int {
+	return err.Code
+}
+
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+
+	if errors.Is(err, ErrInvalidArguments) {
+		return 2
+	}
+
+	return 1
+}
+
+func Exit(err error) {
+	exit(os.Stderr, err)
+}
+
+func (command *Command) Main() {
+	stderr := command.Stderr
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	exit(stderr, command.Run(os.Args, nil))
+}
+
+func exit(w io.Writer, err error) {
+	if err != nil && err.Error() != "" {
+		fmt.Fprintln(w, err.Error())
+	}
+
+	os.Exit(ExitCode(err))
+}
@@ -1,10 +1,12 @@
 module github.com/thamaji/cli
 
-go 1.17
+go 1.18
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/thamaji/tablewriter v0.0.0-20201030131934-c31ca4f2fd34
 	golang.org/x/term v0.0.0-20210927222741-03fcf44c2211
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
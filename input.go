@@ -6,17 +6,17 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"time"
 
 	"golang.org/x/term"
 )
 
 func ReadInputBool(msg string) (bool, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return false, errors.New("stdin is not a terminal")
-	}
+	return readInputBool(os.Stdin, os.Stdout, msg)
+}
 
-	fmt.Fprint(os.Stdout, msg+": ")
-	ans, err := readline(os.Stdin)
+func readInputBool(in io.Reader, out io.Writer, msg string) (bool, error) {
+	ans, err := readInput(in, out, msg)
 	if err != nil {
 		return false, err
 	}
@@ -30,18 +30,16 @@ func ReadInputBool(msg string) (bool, error) {
 }
 
 func ReadPasswordBool(msg string) (bool, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return false, errors.New("stdin is not a terminal")
-	}
+	return readPasswordBool(os.Stdin, os.Stdout, msg)
+}
 
-	fmt.Fprint(os.Stdout, msg+": ")
-	ans, err := term.ReadPassword(int(os.Stdin.Fd()))
-	fmt.Fprintln(os.Stdout)
+func readPasswordBool(in io.Reader, out io.Writer, msg string) (bool, error) {
+	ans, err := readPassword(in, out, msg)
 	if err != nil {
 		return false, err
 	}
 
-	v, err := strconv.ParseBool(string(ans))
+	v, err := strconv.ParseBool(ans)
 	if err != nil {
 		return false, err
 	}
@@ -50,12 +48,11 @@ func ReadPasswordBool(msg string) (bool, error) {
 }
 
 func ReadInputString(msg string) (string, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return "", errors.New("stdin is not a terminal")
-	}
+	return readInputString(os.Stdin, os.Stdout, msg)
+}
 
-	fmt.Fprint(os.Stdout, msg+": ")
-	ans, err := readline(os.Stdin)
+func readInputString(in io.Reader, out io.Writer, msg string) (string, error) {
+	ans, err := readInput(in, out, msg)
 	if err != nil {
 		return "", err
 	}
@@ -64,27 +61,24 @@ func ReadInputString(msg string) (string, error) {
 }
 
 func ReadPasswordString(msg string) (string, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return "", errors.New("stdin is not a terminal")
-	}
+	return readPasswordString(os.Stdin, os.Stdout, msg)
+}
 
-	fmt.Fprint(os.Stdout, msg+": ")
-	ans, err := term.ReadPassword(int(os.Stdin.Fd()))
-	fmt.Fprintln(os.Stdout)
+func readPasswordString(in io.Reader, out io.Writer, msg string) (string, error) {
+	ans, err := readPassword(in, out, msg)
 	if err != nil {
 		return "", err
 	}
 
-	return string(ans), nil
+	return ans, nil
 }
 
 func ReadInputInt(msg string) (int, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return 0, errors.New("stdin is not a terminal")
-	}
+	return readInputInt(os.Stdin, os.Stdout, msg)
+}
 
-	fmt.Fprint(os.Stdout, msg+": ")
-	ans, err := readline(os.Stdin)
+func readInputInt(in io.Reader, out io.Writer, msg string) (int, error) {
+	ans, err := readInput(in, out, msg)
 	if err != nil {
 		return 0, err
 	}
@@ -98,18 +92,16 @@ func ReadInputInt(msg string) (int, error) {
 }
 
 func ReadPasswordInt(msg string) (int, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return 0, errors.New("stdin is not a terminal")
-	}
+	return readPasswordInt(os.Stdin, os.Stdout, msg)
+}
 
-	fmt.Fprint(os.Stdout, msg+": ")
-	ans, err := term.ReadPassword(int(os.Stdin.Fd()))
-	fmt.Fprintln(os.Stdout)
+func readPasswordInt(in io.Reader, out io.Writer, msg string) (int, error) {
+	ans, err := readPassword(in, out, msg)
 	if err != nil {
 		return 0, err
 	}
 
-	v, err := strconv.ParseInt(string(ans), 10, 64)
+	v, err := strconv.ParseInt(ans, 10, 64)
 	if err != nil {
 		return 0, err
 	}
@@ -118,12 +110,11 @@ func ReadPasswordInt(msg string) (int, error) {
 }
 
 func ReadInputInt32(msg string) (int32, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return 0, errors.New("stdin is not a terminal")
-	}
+	return readInputInt32(os.Stdin, os.Stdout, msg)
+}
 
-	fmt.Fprint(os.Stdout, msg+": ")
-	ans, err := readline(os.Stdin)
+func readInputInt32(in io.Reader, out io.Writer, msg string) (int32, error) {
+	ans, err := readInput(in, out, msg)
 	if err != nil {
 		return 0, err
 	}
@@ -137,18 +128,16 @@ func ReadInputInt32(msg string) (int32, error) {
 }
 
 func ReadPasswordInt32(msg string) (int32, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return 0, errors.New("stdin is not a terminal")
-	}
+	return readPasswordInt32(os.Stdin, os.Stdout, msg)
+}
 
-	fmt.Fprint(os.Stdout, msg+": ")
-	ans, err := term.ReadPassword(int(os.Stdin.Fd()))
-	fmt.Fprintln(os.Stdout)
+func readPasswordInt32(in io.Reader, out io.Writer, msg string) (int32, error) {
+	ans, err := readPassword(in, out, msg)
 	if err != nil {
 		return 0, err
 	}
 
-	v, err := strconv.ParseInt(string(ans), 10, 32)
+	v, err := strconv.ParseInt(ans, 10, 32)
 	if err != nil {
 		return 0, err
 	}
@@ -157,12 +146,11 @@ func ReadPasswordInt32(msg string) (int32, error) {
 }
 
 func ReadInputInt64(msg string) (int64, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return 0, errors.New("stdin is not a terminal")
-	}
+	return readInputInt64(os.Stdin, os.Stdout, msg)
+}
 
-	fmt.Fprint(os.Stdout, msg+": ")
-	ans, err := readline(os.Stdin)
+func readInputInt64(in io.Reader, out io.Writer, msg string) (int64, error) {
+	ans, err := readInput(in, out, msg)
 	if err != nil {
 		return 0, err
 	}
@@ -176,18 +164,16 @@ func ReadInputInt64(msg string) (int64, error) {
 }
 
 func ReadPasswordInt64(msg string) (int64, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return 0, errors.New("stdin is not a terminal")
-	}
+	return readPasswordInt64(os.Stdin, os.Stdout, msg)
+}
 
-	fmt.Fprint(os.Stdout, msg+": ")
-	ans, err := term.ReadPassword(int(os.Stdin.Fd()))
-	fmt.Fprintln(os.Stdout)
+func readPasswordInt64(in io.Reader, out io.Writer, msg string) (int64, error) {
+	ans, err := readPassword(in, out, msg)
 	if err != nil {
 		return 0, err
 	}
 
-	v, err := strconv.ParseInt(string(ans), 10, 64)
+	v, err := strconv.ParseInt(ans, 10, 64)
 	if err != nil {
 		return 0, err
 	}
@@ -196,12 +182,11 @@ func ReadPasswordInt64(msg string) (int64, error) {
 }
 
 func ReadInputFloat32(msg string) (float32, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return 0, errors.New("stdin is not a terminal")
-	}
+	return readInputFloat32(os.Stdin, os.Stdout, msg)
+}
 
-	fmt.Fprint(os.Stdout, msg+": ")
-	ans, err := readline(os.Stdin)
+func readInputFloat32(in io.Reader, out io.Writer, msg string) (float32, error) {
+	ans, err := readInput(in, out, msg)
 	if err != nil {
 		return 0, err
 	}
@@ -215,18 +200,16 @@ func ReadInputFloat32(msg string) (float32, error) {
 }
 
 func ReadPasswordFloat32(msg string) (float32, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return 0, errors.New("stdin is not a terminal")
-	}
+	return readPasswordFloat32(os.Stdin, os.Stdout, msg)
+}
 
-	fmt.Fprint(os.Stdout, msg+": ")
-	ans, err := term.ReadPassword(int(os.Stdin.Fd()))
-	fmt.Fprintln(os.Stdout)
+func readPasswordFloat32(in io.Reader, out io.Writer, msg string) (float32, error) {
+	ans, err := readPassword(in, out, msg)
 	if err != nil {
 		return 0, err
 	}
 
-	v, err := strconv.ParseFloat(string(ans), 32)
+	v, err := strconv.ParseFloat(ans, 32)
 	if err != nil {
 		return 0, err
 	}
@@ -235,12 +218,11 @@ func ReadPasswordFloat32(msg string) (float32, error) {
 }
 
 func ReadInputFloat64(msg string) (float64, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return 0, errors.New("stdin is not a terminal")
-	}
+	return readInputFloat64(os.Stdin, os.Stdout, msg)
+}
 
-	fmt.Fprint(os.Stdout, msg+": ")
-	ans, err := readline(os.Stdin)
+func readInputFloat64(in io.Reader, out io.Writer, msg string) (float64, error) {
+	ans, err := readInput(in, out, msg)
 	if err != nil {
 		return 0, err
 	}
@@ -254,18 +236,74 @@ func ReadInputFloat64(msg string) (float64, error) {
 }
 
 func ReadPasswordFloat64(msg string) (float64, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return 0, errors.New("stdin is not a terminal")
+	return readPasswordFloat64(os.Stdin, os.Stdout, msg)
+}
+
+func readPasswordFloat64(in io.Reader, out io.Writer, msg string) (float64, error) {
+	ans, err := readPassword(in, out, msg)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := strconv.ParseFloat(ans, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return v, nil
+}
+
+func ReadInputDuration(msg string) (time.Duration, error) {
+	return readInputDuration(os.Stdin, os.Stdout, msg)
+}
+
+func readInputDuration(in io.Reader, out io.Writer, msg string) (time.Duration, error) {
+	ans, err := readInput(in, out, msg)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := time.ParseDuration(ans)
+	if err != nil {
+		return 0, err
+	}
+
+	return v, nil
+}
+
+func ReadInputTime(msg string, layouts ...string) (time.Time, error) {
+	return readInputTime(os.Stdin, os.Stdout, msg, layouts...)
+}
+
+func readInputTime(in io.Reader, out io.Writer, msg string, layouts ...string) (time.Time, error) {
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339}
+	}
+
+	ans, err := readInput(in, out, msg)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	v, err := parseTime(ans, layouts)
+	if err != nil {
+		return time.Time{}, err
 	}
 
-	fmt.Fprint(os.Stdout, msg+": ")
-	ans, err := term.ReadPassword(int(os.Stdin.Fd()))
-	fmt.Fprintln(os.Stdout)
+	return v, nil
+}
+
+func ReadInputByteSize(msg string) (ByteSize, error) {
+	return readInputByteSize(os.Stdin, os.Stdout, msg)
+}
+
+func readInputByteSize(in io.Reader, out io.Writer, msg string) (ByteSize, error) {
+	ans, err := readInput(in, out, msg)
 	if err != nil {
 		return 0, err
 	}
 
-	v, err := strconv.ParseFloat(string(ans), 32)
+	v, err := ParseByteSize(ans)
 	if err != nil {
 		return 0, err
 	}
@@ -273,6 +311,38 @@ func ReadPasswordFloat64(msg string) (float64, error) {
 	return v, nil
 }
 
+func readInput(in io.Reader, out io.Writer, msg string) (string, error) {
+	if f, ok := in.(*os.File); ok && !term.IsTerminal(int(f.Fd())) {
+		return "", errors.New("stdin is not a terminal")
+	}
+
+	fmt.Fprint(out, msg+": ")
+	return readline(in)
+}
+
+func readPassword(in io.Reader, out io.Writer, msg string) (string, error) {
+	f, ok := in.(*os.File)
+	if !ok {
+		fmt.Fprint(out, msg+": ")
+		ans, err := readline(in)
+		fmt.Fprintln(out)
+		return ans, err
+	}
+
+	if !term.IsTerminal(int(f.Fd())) {
+		return "", errors.New("stdin is not a terminal")
+	}
+
+	fmt.Fprint(out, msg+": ")
+	ans, err := term.ReadPassword(int(f.Fd()))
+	fmt.Fprintln(out)
+	if err != nil {
+		return "", err
+	}
+
+	return string(ans), nil
+}
+
 func readline(r io.Reader) (string, error) {
 	var bytes [1]byte
 	var buf []byte
@@ -1,8 +1,8 @@
 package cli
 
 import (
-	"errors"
 	"strconv"
+	"strings"
 )
 
 type Option interface {
@@ -13,14 +13,28 @@ type Option interface {
 }
 
 type BoolOption struct {
-	Name        string
-	Short       string
-	Description string
-	Usage       string
-	ArgUsage    string
+	Name         string
+	Short        string
+	EnvVars      []string
+	DefaultValue bool
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
+	Negatable    bool
+	Description  string
+	Usage        string
+	ArgUsage     string
 }
 
 func (option *BoolOption) SetDefaultValue(options map[string]interface{}) {
+	if !option.DefaultValue && !option.HasDefault {
+		return
+	}
+	options[option.Name] = option.DefaultValue
+}
+
+func (option *BoolOption) Key() string {
+	return option.Name
 }
 
 func (option *BoolOption) Keywords() []string {
@@ -32,14 +46,44 @@ func (option *BoolOption) Keywords() []string {
 
 	if option.Name != "" {
 		keywords = append(keywords, "--"+option.Name)
+
+		if option.Negatable {
+			keywords = append(keywords, "--no-"+option.Name)
+		}
 	}
 
 	return keywords
 }
 
+func (option *BoolOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *BoolOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *BoolOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *BoolOption) IsBoolFlag() bool {
+	return true
+}
+
 func (option *BoolOption) Apply(options map[string]interface{}, args ...string) (int, error) {
-	options[option.Name] = true
-	return 0, nil
+	if len(args) < 1 {
+		options[option.Name] = true
+		return 0, nil
+	}
+
+	v, err := strconv.ParseBool(args[0])
+	if err != nil {
+		return 0, invalidValue(option, args[0], err)
+	}
+
+	options[option.Name] = v
+	return 1, nil
 }
 
 func (option *BoolOption) Help() [2]string {
@@ -54,11 +98,27 @@ func (option *BoolOption) Help() [2]string {
 			if usage != "" {
 				usage += ","
 			}
-			usage += "--" + option.Name
+
+			if option.Negatable {
+				usage += "--[no-]" + option.Name
+			} else {
+				usage += "--" + option.Name
+			}
 		}
 	}
 
 	description := option.Description
+	if option.DefaultValue || option.HasDefault {
+		description += " (default: " + strconv.FormatBool(option.DefaultValue) + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
 
 	return [2]string{usage, description}
 }
@@ -66,19 +126,27 @@ func (option *BoolOption) Help() [2]string {
 type StringOption struct {
 	Name         string
 	Short        string
+	EnvVars      []string
 	DefaultValue string
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
 	Description  string
 	Usage        string
 	ArgUsage     string
 }
 
 func (option *StringOption) SetDefaultValue(options map[string]interface{}) {
-	if option.DefaultValue == "" {
+	if option.DefaultValue == "" && !option.HasDefault {
 		return
 	}
 	options[option.Name] = option.DefaultValue
 }
 
+func (option *StringOption) Key() string {
+	return option.Name
+}
+
 func (option *StringOption) Keywords() []string {
 	keywords := []string{}
 
@@ -93,9 +161,21 @@ func (option *StringOption) Keywords() []string {
 	return keywords
 }
 
+func (option *StringOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *StringOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *StringOption) IsPersistent() bool {
+	return option.Persistent
+}
+
 func (option *StringOption) Apply(options map[string]interface{}, args ...string) (int, error) {
-	if len(args) < 1 || (len(args[0]) >= 2 && args[0][0] == '-') {
-		return 0, errors.New("missing required value: " + option.usage())
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
 	}
 
 	v := args[0]
@@ -135,6 +215,16 @@ func (option *StringOption) Help() [2]string {
 	description := option.Description
 	if option.DefaultValue != "" {
 		description += " (default: " + option.DefaultValue + ")"
+	} else if option.HasDefault {
+		description += " (default: \"\")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
 	}
 
 	return [2]string{usage, description}
@@ -143,19 +233,27 @@ func (option *StringOption) Help() [2]string {
 type IntOption struct {
 	Name         string
 	Short        string
+	EnvVars      []string
 	DefaultValue int
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
 	Description  string
 	Usage        string
 	ArgUsage     string
 }
 
 func (option *IntOption) SetDefaultValue(options map[string]interface{}) {
-	if option.DefaultValue == 0 {
+	if option.DefaultValue == 0 && !option.HasDefault {
 		return
 	}
 	options[option.Name] = option.DefaultValue
 }
 
+func (option *IntOption) Key() string {
+	return option.Name
+}
+
 func (option *IntOption) Keywords() []string {
 	keywords := []string{}
 
@@ -170,14 +268,26 @@ func (option *IntOption) Keywords() []string {
 	return keywords
 }
 
+func (option *IntOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *IntOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *IntOption) IsPersistent() bool {
+	return option.Persistent
+}
+
 func (option *IntOption) Apply(options map[string]interface{}, args ...string) (int, error) {
-	if len(args) < 1 || (len(args[0]) >= 2 && args[0][0] == '-') {
-		return 0, errors.New("missing required value: " + option.usage())
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
 	}
 
 	v, err := strconv.ParseInt(args[0], 10, 64)
 	if err != nil {
-		return 0, err
+		return 0, invalidValue(option, args[0], err)
 	}
 
 	options[option.Name] = int(v)
@@ -214,29 +324,45 @@ func (option *IntOption) Help() [2]string {
 	usage := option.usage()
 
 	description := option.Description
-	if option.DefaultValue != 0 {
+	if option.DefaultValue != 0 || option.HasDefault {
 		description += " (default: " + strconv.FormatInt(int64(option.DefaultValue), 10) + ")"
 	}
 
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
 	return [2]string{usage, description}
 }
 
 type Int32Option struct {
 	Name         string
 	Short        string
+	EnvVars      []string
 	DefaultValue int32
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
 	Description  string
 	Usage        string
 	ArgUsage     string
 }
 
 func (option *Int32Option) SetDefaultValue(options map[string]interface{}) {
-	if option.DefaultValue == 0 {
+	if option.DefaultValue == 0 && !option.HasDefault {
 		return
 	}
 	options[option.Name] = option.DefaultValue
 }
 
+func (option *Int32Option) Key() string {
+	return option.Name
+}
+
 func (option *Int32Option) Keywords() []string {
 	keywords := []string{}
 
@@ -251,14 +377,26 @@ func (option *Int32Option) Keywords() []string {
 	return keywords
 }
 
+func (option *Int32Option) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *Int32Option) IsRequired() bool {
+	return option.Required
+}
+
+func (option *Int32Option) IsPersistent() bool {
+	return option.Persistent
+}
+
 func (option *Int32Option) Apply(options map[string]interface{}, args ...string) (int, error) {
-	if len(args) < 1 || (len(args[0]) >= 2 && args[0][0] == '-') {
-		return 0, errors.New("missing required value: " + option.usage())
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
 	}
 
 	v, err := strconv.ParseInt(args[0], 10, 32)
 	if err != nil {
-		return 0, err
+		return 0, invalidValue(option, args[0], err)
 	}
 
 	options[option.Name] = int32(v)
@@ -295,29 +433,45 @@ func (option *Int32Option) Help() [2]string {
 	usage := option.usage()
 
 	description := option.Description
-	if option.DefaultValue != 0 {
+	if option.DefaultValue != 0 || option.HasDefault {
 		description += " (default: " + strconv.FormatInt(int64(option.DefaultValue), 10) + ")"
 	}
 
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
 	return [2]string{usage, description}
 }
 
 type Int64Option struct {
 	Name         string
 	Short        string
+	EnvVars      []string
 	DefaultValue int64
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
 	Description  string
 	Usage        string
 	ArgUsage     string
 }
 
 func (option *Int64Option) SetDefaultValue(options map[string]interface{}) {
-	if option.DefaultValue == 0 {
+	if option.DefaultValue == 0 && !option.HasDefault {
 		return
 	}
 	options[option.Name] = option.DefaultValue
 }
 
+func (option *Int64Option) Key() string {
+	return option.Name
+}
+
 func (option *Int64Option) Keywords() []string {
 	keywords := []string{}
 
@@ -332,14 +486,26 @@ func (option *Int64Option) Keywords() []string {
 	return keywords
 }
 
+func (option *Int64Option) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *Int64Option) IsRequired() bool {
+	return option.Required
+}
+
+func (option *Int64Option) IsPersistent() bool {
+	return option.Persistent
+}
+
 func (option *Int64Option) Apply(options map[string]interface{}, args ...string) (int, error) {
-	if len(args) < 1 || (len(args[0]) >= 2 && args[0][0] == '-') {
-		return 0, errors.New("missing required value: " + option.usage())
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
 	}
 
 	v, err := strconv.ParseInt(args[0], 10, 64)
 	if err != nil {
-		return 0, err
+		return 0, invalidValue(option, args[0], err)
 	}
 
 	options[option.Name] = v
@@ -376,29 +542,45 @@ func (option *Int64Option) Help() [2]string {
 	usage := option.usage()
 
 	description := option.Description
-	if option.DefaultValue != 0 {
+	if option.DefaultValue != 0 || option.HasDefault {
 		description += " (default: " + strconv.FormatInt(option.DefaultValue, 10) + ")"
 	}
 
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
 	return [2]string{usage, description}
 }
 
 type Float32Option struct {
 	Name         string
 	Short        string
+	EnvVars      []string
 	DefaultValue float32
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
 	Description  string
 	Usage        string
 	ArgUsage     string
 }
 
 func (option *Float32Option) SetDefaultValue(options map[string]interface{}) {
-	if option.DefaultValue == 0 {
+	if option.DefaultValue == 0 && !option.HasDefault {
 		return
 	}
 	options[option.Name] = option.DefaultValue
 }
 
+func (option *Float32Option) Key() string {
+	return option.Name
+}
+
 func (option *Float32Option) Keywords() []string {
 	keywords := []string{}
 
@@ -413,14 +595,26 @@ func (option *Float32Option) Keywords() []string {
 	return keywords
 }
 
+func (option *Float32Option) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *Float32Option) IsRequired() bool {
+	return option.Required
+}
+
+func (option *Float32Option) IsPersistent() bool {
+	return option.Persistent
+}
+
 func (option *Float32Option) Apply(options map[string]interface{}, args ...string) (int, error) {
-	if len(args) < 1 || (len(args[0]) >= 2 && args[0][0] == '-') {
-		return 0, errors.New("missing required value: " + option.usage())
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
 	}
 
 	v, err := strconv.ParseFloat(args[0], 32)
 	if err != nil {
-		return 0, err
+		return 0, invalidValue(option, args[0], err)
 	}
 
 	options[option.Name] = float32(v)
@@ -457,29 +651,45 @@ func (option *Float32Option) Help() [2]string {
 	usage := option.usage()
 
 	description := option.Description
-	if option.DefaultValue != 0 {
+	if option.DefaultValue != 0 || option.HasDefault {
 		description += " (default: " + strconv.FormatFloat(float64(option.DefaultValue), 'f', -1, 32) + ")"
 	}
 
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
 	return [2]string{usage, description}
 }
 
 type Float64Option struct {
 	Name         string
 	Short        string
+	EnvVars      []string
 	DefaultValue float64
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
 	Description  string
 	Usage        string
 	ArgUsage     string
 }
 
 func (option *Float64Option) SetDefaultValue(options map[string]interface{}) {
-	if option.DefaultValue == 0 {
+	if option.DefaultValue == 0 && !option.HasDefault {
 		return
 	}
 	options[option.Name] = option.DefaultValue
 }
 
+func (option *Float64Option) Key() string {
+	return option.Name
+}
+
 func (option *Float64Option) Keywords() []string {
 	keywords := []string{}
 
@@ -494,14 +704,26 @@ func (option *Float64Option) Keywords() []string {
 	return keywords
 }
 
+func (option *Float64Option) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *Float64Option) IsRequired() bool {
+	return option.Required
+}
+
+func (option *Float64Option) IsPersistent() bool {
+	return option.Persistent
+}
+
 func (option *Float64Option) Apply(options map[string]interface{}, args ...string) (int, error) {
-	if len(args) < 1 || (len(args[0]) >= 2 && args[0][0] == '-') {
-		return 0, errors.New("missing required value: " + option.usage())
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
 	}
 
 	v, err := strconv.ParseFloat(args[0], 64)
 	if err != nil {
-		return 0, err
+		return 0, invalidValue(option, args[0], err)
 	}
 
 	options[option.Name] = v
@@ -538,9 +760,30 @@ func (option *Float64Option) Help() [2]string {
 	usage := option.usage()
 
 	description := option.Description
-	if option.DefaultValue != 0 {
+	if option.DefaultValue != 0 || option.HasDefault {
 		description += " (default: " + strconv.FormatFloat(option.DefaultValue, 'f', -1, 64) + ")"
 	}
 
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
 	return [2]string{usage, description}
 }
+
+func isNegativeNumber(s string) bool {
+	if len(s) < 2 || s[0] != '-' {
+		return false
+	}
+
+	c := s[1]
+	if c == '.' && len(s) > 2 {
+		c = s[2]
+	}
+
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"strings"
+)
+
+type ByteSizeOption struct {
+	Name         string
+	Short        string
+	EnvVars      []string
+	DefaultValue ByteSize
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
+	Description  string
+	Usage        string
+	ArgUsage     string
+}
+
+func (option *ByteSizeOption) SetDefaultValue(options map[string]interface{}) {
+	if option.DefaultValue == 0 && !option.HasDefault {
+		return
+	}
+	options[option.Name] = option.DefaultValue
+}
+
+func (option *ByteSizeOption) Key() string {
+	return option.Name
+}
+
+func (option *ByteSizeOption) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *ByteSizeOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *ByteSizeOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *ByteSizeOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *ByteSizeOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+
+	v, err := ParseByteSize(args[0])
+	if err != nil {
+		return 0, invalidValue(option, args[0], err)
+	}
+
+	options[option.Name] = v
+	return 1, nil
+}
+
+func (option *ByteSizeOption) usage() string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+
+			usage += "--" + option.Name + "="
+
+			if option.ArgUsage != "" {
+				usage += option.ArgUsage
+			} else {
+				usage += "size"
+			}
+		}
+	}
+
+	return usage
+}
+
+func (option *ByteSizeOption) Help() [2]string {
+	usage := option.usage()
+
+	description := option.Description
+	if option.DefaultValue != 0 || option.HasDefault {
+		description += " (default: " + option.DefaultValue.String() + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+)
+
+type CountOption struct {
+	Name         string
+	Short        string
+	EnvVars      []string
+	DefaultValue int
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
+	Description  string
+	Usage        string
+	ArgUsage     string
+}
+
+func (option *CountOption) SetDefaultValue(options map[string]interface{}) {
+	if option.DefaultValue == 0 && !option.HasDefault {
+		return
+	}
+	options[option.Name] = option.DefaultValue
+}
+
+func (option *CountOption) Key() string {
+	return option.Name
+}
+
+func (option *CountOption) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *CountOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *CountOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *CountOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *CountOption) IsBoolFlag() bool {
+	return true
+}
+
+func (option *CountOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 {
+		v, _ := options[option.Name].(int)
+		options[option.Name] = v + 1
+		return 0, nil
+	}
+
+	v, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return 0, invalidValue(option, args[0], err)
+	}
+
+	options[option.Name] = int(v)
+	return 1, nil
+}
+
+func (option *CountOption) Help() [2]string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+			usage += "--" + option.Name
+		}
+	}
+
+	description := option.Description
+	if option.DefaultValue != 0 || option.HasDefault {
+		description += " (default: " + strconv.Itoa(option.DefaultValue) + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
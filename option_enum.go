@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+)
+
+type EnumOption struct {
+	Name         string
+	Short        string
+	EnvVars      []string
+	DefaultValue string
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
+	Choices      []string
+	IgnoreCase   bool
+	Description  string
+	Usage        string
+	ArgUsage     string
+}
+
+func (option *EnumOption) SetDefaultValue(options map[string]interface{}) {
+	if option.DefaultValue == "" && !option.HasDefault {
+		return
+	}
+
+	if v, err := option.choice(option.DefaultValue); err == nil {
+		options[option.Name] = v
+		return
+	}
+	options[option.Name] = option.DefaultValue
+}
+
+func (option *EnumOption) Key() string {
+	return option.Name
+}
+
+func (option *EnumOption) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *EnumOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *EnumOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *EnumOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *EnumOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+
+	v, err := option.choice(args[0])
+	if err != nil {
+		return 0, invalidValue(option, args[0], err)
+	}
+
+	options[option.Name] = v
+	return 1, nil
+}
+
+func (option *EnumOption) choice(value string) (string, error) {
+	for _, choice := range option.Choices {
+		if choice == value || (option.IgnoreCase && strings.EqualFold(choice, value)) {
+			return choice, nil
+		}
+	}
+
+	return "", errors.New("must be one of " + strings.Join(option.Choices, ", "))
+}
+
+func (option *EnumOption) usage() string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+
+			usage += "--" + option.Name + "="
+
+			if option.ArgUsage != "" {
+				usage += option.ArgUsage
+			} else {
+				usage += strings.Join(option.Choices, "|")
+			}
+		}
+	}
+
+	return usage
+}
+
+func (option *EnumOption) Help() [2]string {
+	usage := option.usage()
+
+	description := option.Description
+	if option.DefaultValue != "" || option.HasDefault {
+		description += " (default: " + option.DefaultValue + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Opt[T any] struct {
+	Name        string
+	Short       string
+	EnvVars     []string
+	Default     T
+	HasDefault  bool
+	Required    bool
+	Persistent  bool
+	Description string
+	Usage       string
+	ArgUsage    string
+	Parse       func(string) (T, error)
+}
+
+func (option *Opt[T]) SetDefaultValue(options map[string]interface{}) {
+	if !option.hasDefault() {
+		return
+	}
+	options[option.Name] = option.Default
+}
+
+func (option *Opt[T]) hasDefault() bool {
+	return option.HasDefault || !reflect.ValueOf(&option.Default).Elem().IsZero()
+}
+
+func (option *Opt[T]) Key() string {
+	return option.Name
+}
+
+func (option *Opt[T]) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *Opt[T]) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *Opt[T]) IsRequired() bool {
+	return option.Required
+}
+
+func (option *Opt[T]) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *Opt[T]) IsBoolFlag() bool {
+	_, ok := interface{}(option.Default).(bool)
+	return ok
+}
+
+func (option *Opt[T]) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 && option.IsBoolFlag() {
+		if _, err := option.Apply(options, "true"); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+
+	v, err := option.parse(args[0])
+	if err != nil {
+		return 0, invalidValue(option, args[0], err)
+	}
+
+	options[option.Name] = v
+	return 1, nil
+}
+
+func (option *Opt[T]) parse(s string) (T, error) {
+	if option.Parse != nil {
+		return option.Parse(s)
+	}
+	return parseValue[T](s)
+}
+
+func (option *Opt[T]) usage() string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+
+			usage += "--" + option.Name
+
+			if !option.IsBoolFlag() {
+				usage += "="
+
+				if option.ArgUsage != "" {
+					usage += option.ArgUsage
+				} else {
+					usage += "value"
+				}
+			}
+		}
+	}
+
+	return usage
+}
+
+func (option *Opt[T]) Help() [2]string {
+	usage := option.usage()
+
+	description := option.Description
+	if option.hasDefault() {
+		description += " (default: " + fmt.Sprint(option.Default) + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
+
+var errNoParser = errors.New("no parser")
+
+func (option *Opt[T]) hasParser() bool {
+	if option.Parse != nil {
+		return true
+	}
+
+	_, err := parseValue[T]("")
+	return !errors.Is(err, errNoParser)
+}
+
+func parseValue[T any](s string) (T, error) {
+	var v T
+	var err error
+
+	switch p := interface{}(&v).(type) {
+	case *string:
+		*p = s
+	case *bool:
+		*p, err = strconv.ParseBool(s)
+	case *int:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, 0)
+		*p = int(n)
+	case *int32:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, 32)
+		*p = int32(n)
+	case *int64:
+		*p, err = strconv.ParseInt(s, 10, 64)
+	case *uint:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, 0)
+		*p = uint(n)
+	case *uint32:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, 32)
+		*p = uint32(n)
+	case *uint64:
+		*p, err = strconv.ParseUint(s, 10, 64)
+	case *float32:
+		var n float64
+		n, err = strconv.ParseFloat(s, 32)
+		*p = float32(n)
+	case *float64:
+		*p, err = strconv.ParseFloat(s, 64)
+	case *time.Duration:
+		*p, err = time.ParseDuration(s)
+	case *time.Time:
+		*p, err = parseTime(s, []string{time.RFC3339})
+	case *ByteSize:
+		*p, err = ParseByteSize(s)
+	case encoding.TextUnmarshaler:
+		err = p.UnmarshalText([]byte(s))
+	default:
+		err = fmt.Errorf("%w for %s", errNoParser, typeName[T]())
+	}
+
+	return v, err
+}
+
+func typeName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
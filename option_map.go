@@ -0,0 +1,605 @@
+package cli
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type StringMapOption struct {
+	Name         string
+	Short        string
+	EnvVars      []string
+	DefaultValue map[string]string
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
+	Separator    string
+	NoSplit      bool
+	Description  string
+	Usage        string
+	ArgUsage     string
+}
+
+func (option *StringMapOption) SetDefaultValue(options map[string]interface{}) {
+	if len(option.DefaultValue) == 0 && !option.HasDefault {
+		return
+	}
+
+	v := make(map[string]string, len(option.DefaultValue))
+	for key, value := range option.DefaultValue {
+		v[key] = value
+	}
+
+	options[option.Name] = v
+}
+
+func (option *StringMapOption) Key() string {
+	return option.Name
+}
+
+func (option *StringMapOption) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *StringMapOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *StringMapOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *StringMapOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *StringMapOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+
+	v, ok := options[option.Name].(map[string]string)
+	if !ok {
+		v = map[string]string{}
+	}
+
+	for _, arg := range splitValue(args[0], option.separator(), option.NoSplit) {
+		key, value, err := splitKeyValue(arg)
+		if err != nil {
+			return 0, invalidValue(option, arg, err)
+		}
+
+		if _, ok := v[key]; ok {
+			return 0, invalidValue(option, arg, errors.New("duplicate key "+strconv.Quote(key)))
+		}
+
+		v[key] = value
+	}
+
+	options[option.Name] = v
+	return 1, nil
+}
+
+func (option *StringMapOption) separator() string {
+	if option.Separator == "" {
+		return ","
+	}
+	return option.Separator
+}
+
+func (option *StringMapOption) usage() string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+
+			usage += "--" + option.Name + "="
+
+			if option.ArgUsage != "" {
+				usage += option.ArgUsage
+			} else {
+				usage += "key=string" + repeatUsage(option.separator(), option.NoSplit)
+			}
+		}
+	}
+
+	return usage
+}
+
+func (option *StringMapOption) Help() [2]string {
+	usage := option.usage()
+
+	description := option.Description
+	if len(option.DefaultValue) > 0 || option.HasDefault {
+		values := make([]string, 0, len(option.DefaultValue))
+		for key, value := range option.DefaultValue {
+			values = append(values, key+"="+value)
+		}
+		sort.Strings(values)
+		description += " (default: " + strings.Join(values, option.separator()) + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
+
+type IntMapOption struct {
+	Name         string
+	Short        string
+	EnvVars      []string
+	DefaultValue map[string]int
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
+	Separator    string
+	NoSplit      bool
+	Description  string
+	Usage        string
+	ArgUsage     string
+}
+
+func (option *IntMapOption) SetDefaultValue(options map[string]interface{}) {
+	if len(option.DefaultValue) == 0 && !option.HasDefault {
+		return
+	}
+
+	v := make(map[string]int, len(option.DefaultValue))
+	for key, value := range option.DefaultValue {
+		v[key] = value
+	}
+
+	options[option.Name] = v
+}
+
+func (option *IntMapOption) Key() string {
+	return option.Name
+}
+
+func (option *IntMapOption) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *IntMapOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *IntMapOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *IntMapOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *IntMapOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+
+	v, ok := options[option.Name].(map[string]int)
+	if !ok {
+		v = map[string]int{}
+	}
+
+	for _, arg := range splitValue(args[0], option.separator(), option.NoSplit) {
+		key, value, err := splitKeyValue(arg)
+		if err != nil {
+			return 0, invalidValue(option, arg, err)
+		}
+
+		if _, ok := v[key]; ok {
+			return 0, invalidValue(option, arg, errors.New("duplicate key "+strconv.Quote(key)))
+		}
+
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, invalidValue(option, arg, err)
+		}
+
+		v[key] = int(n)
+	}
+
+	options[option.Name] = v
+	return 1, nil
+}
+
+func (option *IntMapOption) separator() string {
+	if option.Separator == "" {
+		return ","
+	}
+	return option.Separator
+}
+
+func (option *IntMapOption) usage() string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+
+			usage += "--" + option.Name + "="
+
+			if option.ArgUsage != "" {
+				usage += option.ArgUsage
+			} else {
+				usage += "key=number" + repeatUsage(option.separator(), option.NoSplit)
+			}
+		}
+	}
+
+	return usage
+}
+
+func (option *IntMapOption) Help() [2]string {
+	usage := option.usage()
+
+	description := option.Description
+	if len(option.DefaultValue) > 0 || option.HasDefault {
+		values := make([]string, 0, len(option.DefaultValue))
+		for key, value := range option.DefaultValue {
+			values = append(values, key+"="+strconv.FormatInt(int64(value), 10))
+		}
+		sort.Strings(values)
+		description += " (default: " + strings.Join(values, option.separator()) + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
+
+type Int64MapOption struct {
+	Name         string
+	Short        string
+	EnvVars      []string
+	DefaultValue map[string]int64
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
+	Separator    string
+	NoSplit      bool
+	Description  string
+	Usage        string
+	ArgUsage     string
+}
+
+func (option *Int64MapOption) SetDefaultValue(options map[string]interface{}) {
+	if len(option.DefaultValue) == 0 && !option.HasDefault {
+		return
+	}
+
+	v := make(map[string]int64, len(option.DefaultValue))
+	for key, value := range option.DefaultValue {
+		v[key] = value
+	}
+
+	options[option.Name] = v
+}
+
+func (option *Int64MapOption) Key() string {
+	return option.Name
+}
+
+func (option *Int64MapOption) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *Int64MapOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *Int64MapOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *Int64MapOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *Int64MapOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+
+	v, ok := options[option.Name].(map[string]int64)
+	if !ok {
+		v = map[string]int64{}
+	}
+
+	for _, arg := range splitValue(args[0], option.separator(), option.NoSplit) {
+		key, value, err := splitKeyValue(arg)
+		if err != nil {
+			return 0, invalidValue(option, arg, err)
+		}
+
+		if _, ok := v[key]; ok {
+			return 0, invalidValue(option, arg, errors.New("duplicate key "+strconv.Quote(key)))
+		}
+
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, invalidValue(option, arg, err)
+		}
+
+		v[key] = n
+	}
+
+	options[option.Name] = v
+	return 1, nil
+}
+
+func (option *Int64MapOption) separator() string {
+	if option.Separator == "" {
+		return ","
+	}
+	return option.Separator
+}
+
+func (option *Int64MapOption) usage() string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+
+			usage += "--" + option.Name + "="
+
+			if option.ArgUsage != "" {
+				usage += option.ArgUsage
+			} else {
+				usage += "key=number" + repeatUsage(option.separator(), option.NoSplit)
+			}
+		}
+	}
+
+	return usage
+}
+
+func (option *Int64MapOption) Help() [2]string {
+	usage := option.usage()
+
+	description := option.Description
+	if len(option.DefaultValue) > 0 || option.HasDefault {
+		values := make([]string, 0, len(option.DefaultValue))
+		for key, value := range option.DefaultValue {
+			values = append(values, key+"="+strconv.FormatInt(value, 10))
+		}
+		sort.Strings(values)
+		description += " (default: " + strings.Join(values, option.separator()) + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
+
+type Float64MapOption struct {
+	Name         string
+	Short        string
+	EnvVars      []string
+	DefaultValue map[string]float64
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
+	Separator    string
+	NoSplit      bool
+	Description  string
+	Usage        string
+	ArgUsage     string
+}
+
+func (option *Float64MapOption) SetDefaultValue(options map[string]interface{}) {
+	if len(option.DefaultValue) == 0 && !option.HasDefault {
+		return
+	}
+
+	v := make(map[string]float64, len(option.DefaultValue))
+	for key, value := range option.DefaultValue {
+		v[key] = value
+	}
+
+	options[option.Name] = v
+}
+
+func (option *Float64MapOption) Key() string {
+	return option.Name
+}
+
+func (option *Float64MapOption) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *Float64MapOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *Float64MapOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *Float64MapOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *Float64MapOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+
+	v, ok := options[option.Name].(map[string]float64)
+	if !ok {
+		v = map[string]float64{}
+	}
+
+	for _, arg := range splitValue(args[0], option.separator(), option.NoSplit) {
+		key, value, err := splitKeyValue(arg)
+		if err != nil {
+			return 0, invalidValue(option, arg, err)
+		}
+
+		if _, ok := v[key]; ok {
+			return 0, invalidValue(option, arg, errors.New("duplicate key "+strconv.Quote(key)))
+		}
+
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, invalidValue(option, arg, err)
+		}
+
+		v[key] = n
+	}
+
+	options[option.Name] = v
+	return 1, nil
+}
+
+func (option *Float64MapOption) separator() string {
+	if option.Separator == "" {
+		return ","
+	}
+	return option.Separator
+}
+
+func (option *Float64MapOption) usage() string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+
+			usage += "--" + option.Name + "="
+
+			if option.ArgUsage != "" {
+				usage += option.ArgUsage
+			} else {
+				usage += "key=number" + repeatUsage(option.separator(), option.NoSplit)
+			}
+		}
+	}
+
+	return usage
+}
+
+func (option *Float64MapOption) Help() [2]string {
+	usage := option.usage()
+
+	description := option.Description
+	if len(option.DefaultValue) > 0 || option.HasDefault {
+		values := make([]string, 0, len(option.DefaultValue))
+		for key, value := range option.DefaultValue {
+			values = append(values, key+"="+strconv.FormatFloat(value, 'f', -1, 64))
+		}
+		sort.Strings(values)
+		description += " (default: " + strings.Join(values, option.separator()) + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
+
+func splitKeyValue(arg string) (string, string, error) {
+	i := strings.Index(arg, "=")
+	if i < 0 {
+		return "", "", errors.New("missing '='")
+	}
+
+	key := arg[:i]
+	if key == "" {
+		return "", "", errors.New("empty key")
+	}
+
+	return key, arg[i+1:], nil
+}
@@ -0,0 +1,523 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+)
+
+type StringSliceOption struct {
+	Name         string
+	Short        string
+	EnvVars      []string
+	DefaultValue []string
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
+	Separator    string
+	NoSplit      bool
+	Description  string
+	Usage        string
+	ArgUsage     string
+}
+
+func (option *StringSliceOption) SetDefaultValue(options map[string]interface{}) {
+	if len(option.DefaultValue) == 0 && !option.HasDefault {
+		return
+	}
+	options[option.Name] = append([]string{}, option.DefaultValue...)
+}
+
+func (option *StringSliceOption) Key() string {
+	return option.Name
+}
+
+func (option *StringSliceOption) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *StringSliceOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *StringSliceOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *StringSliceOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *StringSliceOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+
+	v, _ := options[option.Name].([]string)
+	for _, arg := range splitValue(args[0], option.separator(), option.NoSplit) {
+		v = append(v, arg)
+	}
+
+	options[option.Name] = v
+	return 1, nil
+}
+
+func (option *StringSliceOption) separator() string {
+	if option.Separator == "" {
+		return ","
+	}
+	return option.Separator
+}
+
+func (option *StringSliceOption) usage() string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+
+			usage += "--" + option.Name + "="
+
+			if option.ArgUsage != "" {
+				usage += option.ArgUsage
+			} else {
+				usage += "string" + repeatUsage(option.separator(), option.NoSplit)
+			}
+		}
+	}
+
+	return usage
+}
+
+func (option *StringSliceOption) Help() [2]string {
+	usage := option.usage()
+
+	description := option.Description
+	if len(option.DefaultValue) > 0 || option.HasDefault {
+		values := make([]string, 0, len(option.DefaultValue))
+		for _, v := range option.DefaultValue {
+			values = append(values, v)
+		}
+		description += " (default: " + strings.Join(values, option.separator()) + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
+
+type IntSliceOption struct {
+	Name         string
+	Short        string
+	EnvVars      []string
+	DefaultValue []int
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
+	Separator    string
+	NoSplit      bool
+	Description  string
+	Usage        string
+	ArgUsage     string
+}
+
+func (option *IntSliceOption) SetDefaultValue(options map[string]interface{}) {
+	if len(option.DefaultValue) == 0 && !option.HasDefault {
+		return
+	}
+	options[option.Name] = append([]int{}, option.DefaultValue...)
+}
+
+func (option *IntSliceOption) Key() string {
+	return option.Name
+}
+
+func (option *IntSliceOption) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *IntSliceOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *IntSliceOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *IntSliceOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *IntSliceOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+
+	v, _ := options[option.Name].([]int)
+	for _, arg := range splitValue(args[0], option.separator(), option.NoSplit) {
+		n, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return 0, invalidValue(option, arg, err)
+		}
+
+		v = append(v, int(n))
+	}
+
+	options[option.Name] = v
+	return 1, nil
+}
+
+func (option *IntSliceOption) separator() string {
+	if option.Separator == "" {
+		return ","
+	}
+	return option.Separator
+}
+
+func (option *IntSliceOption) usage() string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+
+			usage += "--" + option.Name + "="
+
+			if option.ArgUsage != "" {
+				usage += option.ArgUsage
+			} else {
+				usage += "number" + repeatUsage(option.separator(), option.NoSplit)
+			}
+		}
+	}
+
+	return usage
+}
+
+func (option *IntSliceOption) Help() [2]string {
+	usage := option.usage()
+
+	description := option.Description
+	if len(option.DefaultValue) > 0 || option.HasDefault {
+		values := make([]string, 0, len(option.DefaultValue))
+		for _, v := range option.DefaultValue {
+			values = append(values, strconv.FormatInt(int64(v), 10))
+		}
+		description += " (default: " + strings.Join(values, option.separator()) + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
+
+type Int64SliceOption struct {
+	Name         string
+	Short        string
+	EnvVars      []string
+	DefaultValue []int64
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
+	Separator    string
+	NoSplit      bool
+	Description  string
+	Usage        string
+	ArgUsage     string
+}
+
+func (option *Int64SliceOption) SetDefaultValue(options map[string]interface{}) {
+	if len(option.DefaultValue) == 0 && !option.HasDefault {
+		return
+	}
+	options[option.Name] = append([]int64{}, option.DefaultValue...)
+}
+
+func (option *Int64SliceOption) Key() string {
+	return option.Name
+}
+
+func (option *Int64SliceOption) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *Int64SliceOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *Int64SliceOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *Int64SliceOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *Int64SliceOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+
+	v, _ := options[option.Name].([]int64)
+	for _, arg := range splitValue(args[0], option.separator(), option.NoSplit) {
+		n, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return 0, invalidValue(option, arg, err)
+		}
+
+		v = append(v, n)
+	}
+
+	options[option.Name] = v
+	return 1, nil
+}
+
+func (option *Int64SliceOption) separator() string {
+	if option.Separator == "" {
+		return ","
+	}
+	return option.Separator
+}
+
+func (option *Int64SliceOption) usage() string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+
+			usage += "--" + option.Name + "="
+
+			if option.ArgUsage != "" {
+				usage += option.ArgUsage
+			} else {
+				usage += "number" + repeatUsage(option.separator(), option.NoSplit)
+			}
+		}
+	}
+
+	return usage
+}
+
+func (option *Int64SliceOption) Help() [2]string {
+	usage := option.usage()
+
+	description := option.Description
+	if len(option.DefaultValue) > 0 || option.HasDefault {
+		values := make([]string, 0, len(option.DefaultValue))
+		for _, v := range option.DefaultValue {
+			values = append(values, strconv.FormatInt(v, 10))
+		}
+		description += " (default: " + strings.Join(values, option.separator()) + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
+
+type Float64SliceOption struct {
+	Name         string
+	Short        string
+	EnvVars      []string
+	DefaultValue []float64
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
+	Separator    string
+	NoSplit      bool
+	Description  string
+	Usage        string
+	ArgUsage     string
+}
+
+func (option *Float64SliceOption) SetDefaultValue(options map[string]interface{}) {
+	if len(option.DefaultValue) == 0 && !option.HasDefault {
+		return
+	}
+	options[option.Name] = append([]float64{}, option.DefaultValue...)
+}
+
+func (option *Float64SliceOption) Key() string {
+	return option.Name
+}
+
+func (option *Float64SliceOption) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *Float64SliceOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *Float64SliceOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *Float64SliceOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *Float64SliceOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+
+	v, _ := options[option.Name].([]float64)
+	for _, arg := range splitValue(args[0], option.separator(), option.NoSplit) {
+		n, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return 0, invalidValue(option, arg, err)
+		}
+
+		v = append(v, n)
+	}
+
+	options[option.Name] = v
+	return 1, nil
+}
+
+func (option *Float64SliceOption) separator() string {
+	if option.Separator == "" {
+		return ","
+	}
+	return option.Separator
+}
+
+func (option *Float64SliceOption) usage() string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+
+			usage += "--" + option.Name + "="
+
+			if option.ArgUsage != "" {
+				usage += option.ArgUsage
+			} else {
+				usage += "number" + repeatUsage(option.separator(), option.NoSplit)
+			}
+		}
+	}
+
+	return usage
+}
+
+func (option *Float64SliceOption) Help() [2]string {
+	usage := option.usage()
+
+	description := option.Description
+	if len(option.DefaultValue) > 0 || option.HasDefault {
+		values := make([]string, 0, len(option.DefaultValue))
+		for _, v := range option.DefaultValue {
+			values = append(values, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+		description += " (default: " + strings.Join(values, option.separator()) + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
+
+func splitValue(s string, separator string, noSplit bool) []string {
+	if noSplit {
+		return []string{s}
+	}
+	return strings.Split(s, separator)
+}
+
+func repeatUsage(separator string, noSplit bool) string {
+	if noSplit {
+		return "..."
+	}
+	return separator + "..."
+}
@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"strings"
+	"time"
+)
+
+type DurationOption struct {
+	Name         string
+	Short        string
+	EnvVars      []string
+	DefaultValue time.Duration
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
+	Description  string
+	Usage        string
+	ArgUsage     string
+}
+
+func (option *DurationOption) SetDefaultValue(options map[string]interface{}) {
+	if option.DefaultValue == 0 && !option.HasDefault {
+		return
+	}
+	options[option.Name] = option.DefaultValue
+}
+
+func (option *DurationOption) Key() string {
+	return option.Name
+}
+
+func (option *DurationOption) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *DurationOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *DurationOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *DurationOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *DurationOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+
+	v, err := time.ParseDuration(args[0])
+	if err != nil {
+		return 0, invalidValue(option, args[0], err)
+	}
+
+	options[option.Name] = v
+	return 1, nil
+}
+
+func (option *DurationOption) usage() string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+
+			usage += "--" + option.Name + "="
+
+			if option.ArgUsage != "" {
+				usage += option.ArgUsage
+			} else {
+				usage += "duration"
+			}
+		}
+	}
+
+	return usage
+}
+
+func (option *DurationOption) Help() [2]string {
+	usage := option.usage()
+
+	description := option.Description
+	if option.DefaultValue != 0 || option.HasDefault {
+		description += " (default: " + option.DefaultValue.String() + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
+
+type TimeOption struct {
+	Name         string
+	Short        string
+	EnvVars      []string
+	DefaultValue time.Time
+	HasDefault   bool
+	Required     bool
+	Persistent   bool
+	Layouts      []string
+	Description  string
+	Usage        string
+	ArgUsage     string
+}
+
+func (option *TimeOption) SetDefaultValue(options map[string]interface{}) {
+	if option.DefaultValue.IsZero() && !option.HasDefault {
+		return
+	}
+	options[option.Name] = option.DefaultValue
+}
+
+func (option *TimeOption) Key() string {
+	return option.Name
+}
+
+func (option *TimeOption) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *TimeOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *TimeOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *TimeOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *TimeOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+
+	v, err := parseTime(args[0], option.layouts())
+	if err != nil {
+		return 0, invalidValue(option, args[0], err)
+	}
+
+	options[option.Name] = v
+	return 1, nil
+}
+
+func (option *TimeOption) layouts() []string {
+	if len(option.Layouts) == 0 {
+		return []string{time.RFC3339}
+	}
+	return option.Layouts
+}
+
+func (option *TimeOption) usage() string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+
+			usage += "--" + option.Name + "="
+
+			if option.ArgUsage != "" {
+				usage += option.ArgUsage
+			} else {
+				usage += "time"
+			}
+		}
+	}
+
+	return usage
+}
+
+func (option *TimeOption) Help() [2]string {
+	usage := option.usage()
+
+	description := option.Description
+	if !option.DefaultValue.IsZero() || option.HasDefault {
+		description += " (default: " + option.DefaultValue.Format(option.layouts()[0]) + ")"
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
+
+func parseTime(s string, layouts []string) (time.Time, error) {
+	var err error
+	for _, layout := range layouts {
+		var v time.Time
+		if v, err = time.Parse(layout, s); err == nil {
+			return v, nil
+		}
+	}
+	return time.Time{}, err
+}
@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type ValueOption struct {
+	Name        string
+	Short       string
+	EnvVars     []string
+	Value       flag.Value
+	New         func() flag.Value
+	Required    bool
+	Persistent  bool
+	Description string
+	Usage       string
+	ArgUsage    string
+}
+
+func (option *ValueOption) SetDefaultValue(options map[string]interface{}) {
+	value, err := option.newValue()
+	if err != nil {
+		return
+	}
+	options[option.Name] = value
+}
+
+func (option *ValueOption) Key() string {
+	return option.Name
+}
+
+func (option *ValueOption) Keywords() []string {
+	keywords := []string{}
+
+	if option.Short != "" {
+		keywords = append(keywords, "-"+option.Short)
+	}
+
+	if option.Name != "" {
+		keywords = append(keywords, "--"+option.Name)
+	}
+
+	return keywords
+}
+
+func (option *ValueOption) EnvKeywords() []string {
+	return option.EnvVars
+}
+
+func (option *ValueOption) IsRequired() bool {
+	return option.Required
+}
+
+func (option *ValueOption) IsPersistent() bool {
+	return option.Persistent
+}
+
+func (option *ValueOption) IsBoolFlag() bool {
+	flag, ok := option.prototype().(interface{ IsBoolFlag() bool })
+	return ok && flag.IsBoolFlag()
+}
+
+func (option *ValueOption) Apply(options map[string]interface{}, args ...string) (int, error) {
+	if len(args) < 1 && option.IsBoolFlag() {
+		if _, err := option.Apply(options, "true"); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	if len(args) < 1 {
+		return 0, &MissingValueError{Option: option}
+	}
+
+	// never set into option.Value, it is shared by every run of the command
+	value, ok := options[option.Name].(flag.Value)
+	if !ok {
+		v, err := option.newValue()
+		if err != nil {
+			return 0, invalidValue(option, args[0], err)
+		}
+		value = v
+	}
+
+	if err := value.Set(args[0]); err != nil {
+		return 0, invalidValue(option, args[0], err)
+	}
+
+	options[option.Name] = value
+	return 1, nil
+}
+
+func (option *ValueOption) usage() string {
+	usage := option.Usage
+
+	if usage == "" {
+		if option.Short != "" {
+			usage = "-" + option.Short
+		}
+
+		if option.Name != "" {
+			if usage != "" {
+				usage += ","
+			}
+
+			usage += "--" + option.Name
+
+			if !option.IsBoolFlag() {
+				usage += "="
+
+				if option.ArgUsage != "" {
+					usage += option.ArgUsage
+				} else {
+					usage += "value"
+				}
+			}
+		}
+	}
+
+	return usage
+}
+
+func (option *ValueOption) Help() [2]string {
+	usage := option.usage()
+
+	description := option.Description
+	if value := option.prototype(); value != nil {
+		if v := value.String(); v != "" && !(option.IsBoolFlag() && v == "false") {
+			description += " (default: " + v + ")"
+		}
+	}
+
+	if len(option.EnvVars) > 0 {
+		description += " (env: " + strings.Join(option.EnvVars, ",") + ")"
+	}
+
+	if option.Required {
+		description += " (required)"
+	}
+
+	return [2]string{usage, description}
+}
+
+func TextValue(v encoding.TextUnmarshaler) flag.Value {
+	return &textValue{v}
+}
+
+type textValue struct {
+	v encoding.TextUnmarshaler
+}
+
+func (value *textValue) Set(s string) error {
+	return value.v.UnmarshalText([]byte(s))
+}
+
+func (value *textValue) String() string {
+	if m, ok := value.v.(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(text)
+	}
+	return fmt.Sprint(value.v)
+}
+
+func (option *ValueOption) prototype() flag.Value {
+	if option.Value != nil {
+		return option.Value
+	}
+
+	if option.New != nil {
+		return option.New()
+	}
+
+	return nil
+}
+
+func (option *ValueOption) newValue() (flag.Value, error) {
+	if option.New != nil {
+		return option.New(), nil
+	}
+
+	if option.Value == nil {
+		return nil, errors.New("no value to set")
+	}
+
+	// only plain data is copied, anything holding references must be created by New
+	if v, ok := option.Value.(*textValue); ok {
+		if c, ok := copyValue(v.v); ok {
+			return &textValue{c.(encoding.TextUnmarshaler)}, nil
+		}
+		return nil, fmt.Errorf("%T cannot be copied, set New", v.v)
+	}
+
+	if c, ok := copyValue(option.Value); ok {
+		return c.(flag.Value), nil
+	}
+
+	return nil, fmt.Errorf("%T cannot be copied, set New", option.Value)
+}
+
+func copyValue(v interface{}) (interface{}, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || !isPlainData(rv.Type().Elem()) {
+		return nil, false
+	}
+
+	c := reflect.New(rv.Type().Elem())
+	c.Elem().Set(rv.Elem())
+	return c.Interface(), true
+}
+
+func isPlainData(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return isPlainData(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !isPlainData(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+)
+
+func suggest(name string, candidates []string) []string {
+	threshold := len(strings.TrimLeft(name, "-")) / 3
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	distances := map[string]int{}
+	suggestions := []string{}
+
+	for _, candidate := range candidates {
+		if _, ok := distances[candidate]; ok {
+			continue
+		}
+
+		d := levenshtein(name, candidate)
+		if d <= threshold {
+			distances[candidate] = d
+			suggestions = append(suggestions, candidate)
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if distances[suggestions[i]] != distances[suggestions[j]] {
+			return distances[suggestions[i]] < distances[suggestions[j]]
+		}
+		return suggestions[i] < suggestions[j]
+	})
+
+	return suggestions
+}
+
+func shortSuggestions(key string, options map[string]Option) []string {
+	suggestions := []string{}
+
+	for keyword, option := range options {
+		switch {
+		case len(keyword) == 2 && keyword != key && strings.EqualFold(keyword, key):
+			suggestions = append(suggestions, keyword)
+		case len(keyword) >= 3 && keyword[:2] == "--" && strings.EqualFold(keyword[2:3], key[1:]) && !isNegated(option, keyword):
+			suggestions = append(suggestions, keyword)
+		}
+	}
+
+	sort.Strings(suggestions)
+
+	return suggestions
+}
+
+func didYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return " (did you mean " + strings.Join(suggestions, ", ") + "?)"
+}
+
+func levenshtein(a string, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(t)]
+}
+
+func min3(a int, b int, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
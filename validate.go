@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+)
+
+func (command *Command) Validate() error {
+	problems := command.validate(command.Name, true)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (command *Command) validate(path string, root bool) []string {
+	problems := []string{}
+
+	if command.Version != "" && command.hasKeyword("-"+command.versionShort()) {
+		problems = append(problems, path+": -"+command.versionShort()+" shadows the built-in --version")
+	}
+
+	if !command.NoHelp && command.hasKeyword("-h") {
+		problems = append(problems, path+": -h shadows the built-in --help")
+	}
+
+	names := map[string]bool{}
+	keywords := map[string]Option{}
+
+	for _, option := range command.allOptions(root) {
+		name := optionKey(option)
+
+		if name == "" {
+			problems = append(problems, path+": option without name: "+option.Help()[0])
+		} else if names[name] {
+			problems = append(problems, path+": duplicate option name: "+name)
+		}
+		names[name] = true
+
+		if p, ok := option.(interface{ hasParser() bool }); ok && !p.hasParser() {
+			problems = append(problems, path+": "+option.Help()[0]+": no parser, set Parse")
+		}
+
+		if e, ok := option.(*EnumOption); ok && (e.DefaultValue != "" || e.HasDefault) {
+			if _, err := e.choice(e.DefaultValue); err != nil {
+				problems = append(problems, path+": default "+strconv.Quote(e.DefaultValue)+" of "+option.Help()[0]+" "+err.Error())
+			}
+		}
+
+		if v, ok := option.(*ValueOption); ok {
+			if _, err := v.newValue(); err != nil {
+				problems = append(problems, path+": "+option.Help()[0]+": "+err.Error())
+			}
+		}
+
+		for _, keyword := range option.Keywords() {
+			if !strings.HasPrefix(keyword, "--") && len(keyword) != 2 {
+				problems = append(problems, path+": short option must be a single character: "+keyword)
+			}
+
+			if other, ok := keywords[keyword]; ok && other != option && !command.isBuiltinOption(option) {
+				problems = append(problems, path+": "+keyword+" is used by both "+other.Help()[0]+" and "+option.Help()[0])
+			}
+			keywords[keyword] = option
+		}
+	}
+
+	commands := map[string]bool{}
+
+	for _, subcommand := range command.Commands {
+		for _, name := range append([]string{subcommand.Name}, subcommand.Aliases...) {
+			if commands[name] {
+				problems = append(problems, path+": duplicate command name: "+name)
+			}
+			commands[name] = true
+		}
+
+		problems = append(problems, subcommand.validate(path+" "+subcommand.Name, false)...)
+	}
+
+	return problems
+}
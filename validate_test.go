@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateShadowedBuiltins(t *testing.T) {
+	command := &Command{
+		Name:    "app",
+		Version: "v1.0.0",
+		Options: []Option{
+			&BoolOption{Name: "verbose", Short: "v"},
+			&StringOption{Name: "host", Short: "h"},
+		},
+	}
+
+	var validation *ValidationError
+	if err := command.Validate(); !errors.As(err, &validation) {
+		t.Fatalf("Validate() = %v, want ValidationError", err)
+	}
+
+	want := []string{
+		"app: -v shadows the built-in --version",
+		"app: -h shadows the built-in --help",
+	}
+	if strings.Join(validation.Problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("problems = %q, want %q", validation.Problems, want)
+	}
+}
+
+func TestValidateOptWithoutParser(t *testing.T) {
+	command := &Command{
+		Name:    "app",
+		NoHelp:  true,
+		Options: []Option{&Opt[int32]{Name: "n"}, &Opt[struct{}]{Name: "s"}},
+	}
+
+	var validation *ValidationError
+	if err := command.Validate(); !errors.As(err, &validation) || len(validation.Problems) != 1 {
+		t.Fatalf("Validate() = %v, want one problem for --s", err)
+	}
+}
+
+func TestValidateEnumDefault(t *testing.T) {
+	command := &Command{
+		Name:   "app",
+		NoHelp: true,
+		Options: []Option{
+			&EnumOption{Name: "format", Choices: []string{"json", "yaml"}, DefaultValue: "xml"},
+			&EnumOption{Name: "color", Choices: []string{"auto", "never"}, DefaultValue: "AUTO", IgnoreCase: true},
+		},
+	}
+
+	var validation *ValidationError
+	if err := command.Validate(); !errors.As(err, &validation) || len(validation.Problems) != 1 {
+		t.Fatalf("Validate() = %v, want one problem for --format", err)
+	}
+
+	if !strings.Contains(validation.Problems[0], `"xml"`) {
+		t.Errorf("problem = %q", validation.Problems[0])
+	}
+}